@@ -2,18 +2,282 @@ package main
 
 import (
 	"bufio"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/MdSadiqMd/Scrape404/package/auth"
+	"github.com/MdSadiqMd/Scrape404/package/checkpoint"
+	"github.com/MdSadiqMd/Scrape404/package/network"
 	"github.com/MdSadiqMd/Scrape404/package/server"
+	"github.com/MdSadiqMd/Scrape404/package/types"
 	"github.com/MdSadiqMd/Scrape404/package/utils"
 	"github.com/MdSadiqMd/Scrape404/package/worker"
 )
 
 func main() {
+	// `scrape404 scan --dir ./public --base-url https://...` checks a built site
+	// without prompts or a server, `scrape404 scan --sources ./docs` checks the
+	// links written in Markdown and reStructuredText files
+	args := os.Args[1:]
+	scanCommand := len(args) > 0 && args[0] == "scan"
+	if scanCommand {
+		args = args[1:]
+	}
+	sourceDir := flag.String("dir", "", "With scan, built static site directory to check instead of crawling a server")
+	siteURL := flag.String("base-url", "", "With scan, URL the site in -dir is served at")
+	sourcesDir := flag.String("sources", "", "With scan, directory whose .md, .mdx and .rst files are checked for dead links")
+	resumeID := flag.String("resume", "", "Resume the scan with this ID from its last checkpoint")
+	resultsDir := flag.String("results-dir", "results", "Directory for checkpoints and scan results")
+	checkpointInterval := flag.Int("checkpoint-interval", 30, "Seconds between checkpoints")
+	noCache := flag.Bool("no-cache", false, "Check every link over the network, ignoring the link cache")
+	cacheFile := flag.String("cache-file", "", "Link cache file (default <results-dir>/link-cache.json)")
+	cacheValidTTL := flag.Duration("cache-ttl-valid", 24*time.Hour, "How long valid link results are reused")
+	cacheDeadTTL := flag.Duration("cache-ttl-dead", time.Hour, "How long dead link results are reused")
+	cacheErrorTTL := flag.Duration("cache-ttl-error", 10*time.Minute, "How long network and request errors are reused")
+	creds := auth.New()
+	flag.Func("basic-auth", "Basic auth for one host as host=user:password (repeatable)", creds.AddBasicAuth)
+	flag.Func("bearer-token", "Bearer token for one host as host=token (repeatable)", creds.AddBearerToken)
+	flag.Func("header", "Extra header for one host as host=Name: value (repeatable)", creds.AddHeader)
+	cookiesFile := flag.String("cookies", "", "Netscape cookies.txt file to import")
+	checkPDFs := flag.Bool("check-pdfs", false, "Download same-site PDFs and check the links inside them")
+	maxPDFSize := flag.Int("max-pdf-size", 20, "Largest PDF to download in MB")
+	maxJSErrors := flag.Int("max-js-errors", -1, "Fail when more distinct JavaScript errors are found in Playwright mode (-1 for no limit)")
+	maxJSExceptions := flag.Int("max-js-exceptions", -1, "Fail when more distinct uncaught exceptions or rejections are found (-1 for no limit)")
+	contextResetEvery := flag.Int("context-reset-every", 0, "Recreate each Playwright browser context after this many pages (0 never)")
+	var blockTypes, blockURLs, renderURLs, exploreSkip, exploreSkipSelectors []string
+	browserCfg := types.BrowserConfig{}
+	flag.StringVar(&browserCfg.Engine, "browser", types.EngineChromium, "Playwright browser engine: chromium, firefox or webkit")
+	flag.BoolVar(&browserCfg.SkipInstall, "skip-install", false, "Do not download the Playwright driver and browsers, use the installed ones")
+	flag.StringVar(&browserCfg.ExecutablePath, "browser-path", "", "Browser executable to launch instead of the bundled one")
+	flag.StringVar(&browserCfg.DriverDir, "driver-dir", "", "Playwright driver directory (default PLAYWRIGHT_DRIVER_PATH or the user cache)")
+	flag.BoolVar(&browserCfg.Headed, "headed", false, "Show the browser window instead of running headless")
+	viewport := flag.String("viewport", "", "Browser viewport as WIDTHxHEIGHT, e.g. 1280x720")
+	flag.StringVar(&browserCfg.Locale, "locale", "", "Browser locale, e.g. en-GB")
+	flag.StringVar(&browserCfg.Timezone, "timezone", "", "Browser timezone, e.g. Europe/London")
+	flag.Func("block", "Abort this Playwright resource type while rendering, e.g. image, media, font (repeatable)", func(s string) error {
+		blockTypes = append(blockTypes, strings.Split(s, ",")...)
+		return nil
+	})
+	flag.Func("block-url", "Abort requests matching this URL glob while rendering, e.g. *googletagmanager.com* (repeatable)", func(s string) error {
+		blockURLs = append(blockURLs, s)
+		return nil
+	})
+	waitUntil := flag.String("wait-until", "networkidle", "Playwright load event to wait for: load, domcontentloaded or networkidle")
+	waitForSelector := flag.String("wait-for-selector", "", "CSS selector to wait for before extracting links in Playwright mode")
+	hybrid := flag.Bool("hybrid", false, "Fetch pages over HTTP, or from disk with scan, and render only those that need JavaScript with Playwright")
+	exploreSPA := flag.Bool("explore-spa", false, "Click links and buttons in nav regions to discover client-side routes in Playwright mode")
+	flag.Func("explore-skip", "With -explore-spa, never click controls whose text contains this, on top of logout, sign out, delete and similar (repeatable)", func(s string) error {
+		exploreSkip = append(exploreSkip, s)
+		return nil
+	})
+	flag.Func("explore-skip-selector", "With -explore-spa, never click controls matching this CSS selector (repeatable)", func(s string) error {
+		exploreSkipSelectors = append(exploreSkipSelectors, s)
+		return nil
+	})
+	notFoundSelector := flag.String("not-found-selector", "", "CSS selector of the client-side not found view, pages showing it are reported as dead")
+	flag.Func("render-url", "In hybrid mode, always render pages matching this URL glob with Playwright (repeatable)", func(s string) error {
+		renderURLs = append(renderURLs, s)
+		return nil
+	})
+	screenshots := flag.Bool("screenshots", false, "Save full-page screenshots of pages with dead links or JavaScript errors in Playwright mode")
+	har := flag.Bool("har", false, "Save a HAR file of the network activity of pages with dead links or JavaScript errors. Every page then gets a fresh browser context, so cookies, cache and storage are not kept between pages and crawls are slower")
+	loginConfig := flag.String("login-config", "", "JSON login flow to run before a Playwright crawl")
+	storageState := flag.String("storage-state", "", "Playwright storage state file to reuse, written after a scripted login (default <results-dir>/storage-state-<login URL hash>.json)")
+	netCfg := &network.Config{}
+	flag.StringVar(&netCfg.ProxyURL, "proxy", "", "Proxy for all requests, http://, https:// or socks5:// URL")
+	flag.Func("no-proxy", "Comma-separated hosts or .domain suffixes that bypass the proxy (repeatable)", netCfg.AddNoProxy)
+	flag.Func("ca-cert", "Extra trusted CA certificate PEM file (repeatable). Playwright pages only load with -browser chromium, which then skips verification of any chain containing it, no safer than -insecure-skip-verify", netCfg.AddCAFile)
+	flag.Func("client-cert", "Client certificate for one host as host=cert.pem:key.pem (repeatable)", netCfg.AddClientCert)
+	flag.BoolVar(&netCfg.InsecureSkipVerify, "insecure-skip-verify", false, "Do not verify TLS certificates")
+	checkMailDomains := flag.Bool("check-mail-domains", false, "Look up MX or A records of mailto: link domains")
+	strictContacts := flag.Bool("strict-contact", false, "Report tel: numbers not in +E.164 format and mailto: domains without a top-level domain as dead")
+	dnsServer := flag.String("dns-server", "", "DNS server as host:port for mail domain lookups instead of the system resolver")
+	flag.CommandLine.Parse(args)
+
+	switch browserCfg.Engine {
+	case types.EngineChromium, types.EngineFirefox, types.EngineWebKit:
+	default:
+		fmt.Printf("Error: unknown browser %q, use chromium, firefox or webkit\n", browserCfg.Engine)
+		os.Exit(1)
+	}
+	if len(netCfg.CAFiles) > 0 && browserCfg.Engine != types.EngineChromium && !netCfg.InsecureSkipVerify {
+		fmt.Printf("Warning: -ca-cert is only trusted by chromium, rendering pages with %s will fail\n", browserCfg.Engine)
+	} else if len(netCfg.CAFiles) > 0 && !netCfg.InsecureSkipVerify {
+		fmt.Println("Warning: in Playwright mode chromium skips verification of any chain containing a -ca-cert certificate, which is no safer than -insecure-skip-verify")
+	}
+	switch *waitUntil {
+	case "load", "domcontentloaded", "networkidle":
+	default:
+		fmt.Printf("Error: unknown wait condition %q, use load, domcontentloaded or networkidle\n", *waitUntil)
+		os.Exit(1)
+	}
+	if *viewport != "" {
+		if _, err := fmt.Sscanf(*viewport, "%dx%d", &browserCfg.ViewportWidth, &browserCfg.ViewportHeight); err != nil {
+			fmt.Printf("Error: invalid viewport %q, expected WIDTHxHEIGHT\n", *viewport)
+			os.Exit(1)
+		}
+	}
+
+	if err := utils.SetNetwork(netCfg); err != nil {
+		fmt.Printf("Error setting up network: %s\n", err)
+		os.Exit(1)
+	}
+	if err := utils.SetContactChecks(*checkMailDomains, *strictContacts, *dnsServer); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		os.Exit(1)
+	}
+
+	if *cookiesFile != "" {
+		if err := creds.LoadCookiesFile(*cookiesFile); err != nil {
+			fmt.Printf("Error loading cookies: %s\n", err)
+			os.Exit(1)
+		}
+	}
+	if err := utils.SetCredentials(creds); err != nil {
+		fmt.Printf("Error setting up credentials: %s\n", err)
+		os.Exit(1)
+	}
+
 	scanner := bufio.NewScanner(os.Stdin)
 
+	var cfg types.ScanConfig
+	var resume *checkpoint.Checkpoint
+	if *resumeID != "" {
+		cp, err := checkpoint.Load(*resultsDir, *resumeID)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			os.Exit(1)
+		}
+		if cp.Completed {
+			fmt.Printf("Scan %s already completed, nothing to resume\n", *resumeID)
+			os.Exit(0)
+		}
+		cfg, resume = cp.Config, cp
+		cfg.CheckpointInterval = *checkpointInterval
+		// The resumed run may be on another machine with its own browser setup
+		cfg.Browser.SkipInstall = browserCfg.SkipInstall
+		cfg.Browser.ExecutablePath = browserCfg.ExecutablePath
+		cfg.Browser.DriverDir = browserCfg.DriverDir
+		cfg.Browser.Headed = browserCfg.Headed
+	} else {
+		if scanCommand {
+			switch {
+			case *sourcesDir != "":
+				cfg = sourcesScanConfig(*sourcesDir)
+			case *sourceDir != "" && *siteURL != "":
+				cfg = fileScanConfig(*sourceDir, *siteURL)
+				cfg.Hybrid = *hybrid
+			default:
+				fmt.Println("Usage: scrape404 scan --dir <site directory> --base-url <url> [flags]")
+				fmt.Println("       scrape404 scan --sources <docs directory> [flags]")
+				os.Exit(2)
+			}
+		} else {
+			cfg = promptScanConfig(scanner, *hybrid)
+		}
+		cfg.ScanID = checkpoint.NewScanID()
+		cfg.ResultsDir = *resultsDir
+		cfg.CheckpointInterval = *checkpointInterval
+		cfg.CheckPDFs = *checkPDFs
+		cfg.MaxPDFSizeMB = *maxPDFSize
+		cfg.MaxJSErrors = *maxJSErrors
+		cfg.MaxJSExceptions = *maxJSExceptions
+		cfg.ContextResetEvery = *contextResetEvery
+		cfg.Browser = browserCfg
+		cfg.BlockResourceTypes = blockTypes
+		cfg.BlockURLPatterns = blockURLs
+		cfg.WaitUntil = *waitUntil
+		cfg.WaitForSelector = *waitForSelector
+		cfg.RenderURLPatterns = renderURLs
+		cfg.ExploreSPA = *exploreSPA
+		cfg.ExploreSkip = exploreSkip
+		cfg.ExploreSkipSelectors = exploreSkipSelectors
+		cfg.NotFoundSelector = *notFoundSelector
+		cfg.CaptureScreenshots = *screenshots
+		cfg.CaptureHAR = *har
+		cfg.LoginConfig = *loginConfig
+		cfg.StorageState = *storageState
+	}
+
+	var cache *utils.LinkCache
+	if !*noCache {
+		path := *cacheFile
+		if path == "" {
+			path = filepath.Join(*resultsDir, "link-cache.json")
+		}
+		var err error
+		cache, err = utils.NewLinkCache(path, *cacheValidTTL, *cacheDeadTTL, *cacheErrorTTL)
+		if err != nil {
+			fmt.Printf("Error loading link cache: %s\n", err)
+			os.Exit(1)
+		}
+		utils.SetLinkCache(cache, cfg.URL)
+	}
+
+	if cfg.SourceDir != "" {
+		if err := utils.SetLocalSite(cfg.URL, cfg.SourceDir); err != nil {
+			fmt.Printf("Error: %s\n", err)
+			os.Exit(1)
+		}
+	} else if cfg.SourcesDir == "" {
+		port := utils.PromptString(scanner, "Enter port for HTTP server", "8080")
+		go server.StartServer(port)
+	}
+
+	scanErr := worker.Scan(cfg, resume)
+
+	if cache != nil {
+		if err := cache.Save(); err != nil {
+			fmt.Printf("Error saving link cache: %s\n", err)
+		}
+	}
+	if errors.Is(scanErr, worker.ErrInterrupted) {
+		fmt.Printf("\nScan interrupted, resume with: --resume %s\n", cfg.ScanID)
+		os.Exit(130)
+	}
+	if scanErr != nil {
+		fmt.Printf("\nScan failed: %s\n", scanErr)
+		os.Exit(1)
+	}
+}
+
+// fileScanConfig checks every page of a local site, failing on dead links so it
+// can gate deployments
+func fileScanConfig(dir, baseURL string) types.ScanConfig {
+	return types.ScanConfig{
+		URL:             baseURL,
+		SourceDir:       dir,
+		FailOnDeadLinks: true,
+		Parallelism:     4,
+		TimeoutSec:      30,
+		UserAgent:       "DeadLinkChecker/1.0",
+	}
+}
+
+// sourcesScanConfig checks the links of every document below dir, failing on
+// dead links like fileScanConfig
+func sourcesScanConfig(dir string) types.ScanConfig {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	return types.ScanConfig{
+		URL:             dir,
+		SourcesDir:      dir,
+		FailOnDeadLinks: true,
+		Parallelism:     4,
+		TimeoutSec:      30,
+		UserAgent:       "DeadLinkChecker/1.0",
+	}
+}
+
+// promptScanConfig asks for the scan settings, skipping the Playwright
+// question when hybrid mode was already chosen with -hybrid
+func promptScanConfig(scanner *bufio.Scanner, hybrid bool) types.ScanConfig {
 	url := utils.PromptString(scanner, "Enter URL to scrape for dead links", "")
 	if url == "" {
 		fmt.Println("Error: URL cannot be empty")
@@ -25,15 +289,22 @@ func main() {
 	parallel := utils.PromptInt(scanner, "Enter number of parallel scrapers", 2)
 	timeout := utils.PromptInt(scanner, "Enter request timeout in seconds", 30)
 	userAgent := utils.PromptString(scanner, "Enter user agent", "DeadLinkChecker/1.0")
-	port := utils.PromptString(scanner, "Enter port for HTTP server", "8080")
-	jsInput := utils.PromptString(scanner, "Use Playwright for JavaScript-enabled websites? (y/n)", "n")
-	usePlaywright := strings.ToLower(jsInput) == "y" || strings.ToLower(jsInput) == "yes"
-
-	go server.StartServer(port)
+	usePlaywright := false
+	if !hybrid {
+		jsInput := strings.ToLower(utils.PromptString(scanner, "Use Playwright for JavaScript-enabled websites? (y/n/auto)", "n"))
+		usePlaywright = jsInput == "y" || jsInput == "yes"
+		// auto crawls over HTTP and renders only pages that need JavaScript
+		hybrid = jsInput == "auto"
+	}
 
-	if usePlaywright {
-		worker.ScrapeWithPlaywright(url, depth, delay, parallel, timeout, userAgent)
-	} else {
-		worker.ScrapeWebsite(url, depth, delay, parallel, timeout, userAgent)
+	return types.ScanConfig{
+		URL:           url,
+		MaxDepth:      depth,
+		DelayMs:       delay,
+		Parallelism:   parallel,
+		TimeoutSec:    timeout,
+		UserAgent:     userAgent,
+		UsePlaywright: usePlaywright,
+		Hybrid:        hybrid,
 	}
 }
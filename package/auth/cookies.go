@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Cookie struct {
+	Domain            string
+	IncludeSubdomains bool
+	Path              string
+	Secure            bool
+	HttpOnly          bool
+	Expires           int64
+	Name              string
+	Value             string
+}
+
+// LoadCookiesFile imports a Netscape cookies.txt file as exported by browsers and curl
+func (c *Credentials) LoadCookiesFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+
+		httpOnly := false
+		if strings.HasPrefix(line, "#HttpOnly_") {
+			line = strings.TrimPrefix(line, "#HttpOnly_")
+			httpOnly = true
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			return fmt.Errorf("%s:%d: expected 7 tab-separated fields, got %d", path, lineNum, len(fields))
+		}
+		expires, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s:%d: invalid expiry %q", path, lineNum, fields[4])
+		}
+
+		c.cookies = append(c.cookies, Cookie{
+			Domain:            strings.ToLower(fields[0]),
+			IncludeSubdomains: strings.EqualFold(fields[1], "TRUE"),
+			Path:              fields[2],
+			Secure:            strings.EqualFold(fields[3], "TRUE"),
+			HttpOnly:          httpOnly,
+			Expires:           expires,
+			Name:              fields[5],
+			Value:             fields[6],
+		})
+	}
+	return scanner.Err()
+}
+
+func (c *Credentials) Cookies() []Cookie {
+	if c == nil {
+		return nil
+	}
+	return c.cookies
+}
+
+// matches reports whether the cookie is sent to host
+func (ck Cookie) matches(host string) bool {
+	domain := strings.ToLower(strings.TrimPrefix(ck.Domain, "."))
+	return host == domain || (ck.IncludeSubdomains && strings.HasSuffix(host, "."+domain))
+}
+
+// HTTPCookie returns the cookie together with the URL it should be stored under
+func (ck Cookie) HTTPCookie() (*url.URL, *http.Cookie) {
+	scheme := "http"
+	if ck.Secure {
+		scheme = "https"
+	}
+	u := &url.URL{Scheme: scheme, Host: strings.TrimPrefix(ck.Domain, "."), Path: ck.Path}
+
+	cookie := &http.Cookie{
+		Name:     ck.Name,
+		Value:    ck.Value,
+		Path:     ck.Path,
+		Secure:   ck.Secure,
+		HttpOnly: ck.HttpOnly,
+	}
+	// Only domain cookies carry a Domain attribute, host-only cookies stay on their exact host
+	if ck.IncludeSubdomains {
+		cookie.Domain = ck.Domain
+	}
+	if ck.Expires > 0 {
+		cookie.Expires = time.Unix(ck.Expires, 0)
+	}
+	return u, cookie
+}
+
+// CookieJar returns a jar seeded with the imported cookies that ignores cookies
+// set by responses, so link checks stay independent of each other
+func (c *Credentials) CookieJar() (http.CookieJar, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, ck := range c.Cookies() {
+		u, cookie := ck.HTTPCookie()
+		jar.SetCookies(u, []*http.Cookie{cookie})
+	}
+	return readOnlyJar{jar}, nil
+}
+
+type readOnlyJar struct {
+	*cookiejar.Jar
+}
+
+func (readOnlyJar) SetCookies(*url.URL, []*http.Cookie) {}
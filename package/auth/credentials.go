@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+type HostCredentials struct {
+	Username    string
+	Password    string
+	BearerToken string
+	Headers     map[string]string
+}
+
+// Credentials holds per-host auth settings. Headers are only ever produced for
+// the exact host they were configured for, so they never leak to external links.
+type Credentials struct {
+	hosts   map[string]*HostCredentials
+	cookies []Cookie
+}
+
+func New() *Credentials {
+	return &Credentials{hosts: make(map[string]*HostCredentials)}
+}
+
+func (c *Credentials) Empty() bool {
+	return c == nil || (len(c.hosts) == 0 && len(c.cookies) == 0)
+}
+
+// HasHeaders reports whether any host has basic auth, a bearer token or extra headers
+func (c *Credentials) HasHeaders() bool {
+	return c != nil && len(c.hosts) > 0
+}
+
+// AddBasicAuth parses "host=user:password"
+func (c *Credentials) AddBasicAuth(spec string) error {
+	host, value, err := splitHostSpec(spec)
+	if err != nil {
+		return err
+	}
+	user, password, ok := strings.Cut(value, ":")
+	if !ok {
+		return fmt.Errorf("basic auth %q must look like host=user:password", spec)
+	}
+	creds := c.host(host)
+	creds.Username, creds.Password = user, password
+	return nil
+}
+
+// AddBearerToken parses "host=token"
+func (c *Credentials) AddBearerToken(spec string) error {
+	host, token, err := splitHostSpec(spec)
+	if err != nil {
+		return err
+	}
+	c.host(host).BearerToken = token
+	return nil
+}
+
+// AddHeader parses "host=Name: value"
+func (c *Credentials) AddHeader(spec string) error {
+	host, header, err := splitHostSpec(spec)
+	if err != nil {
+		return err
+	}
+	name, value, ok := strings.Cut(header, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("header %q must look like host=Name: value", spec)
+	}
+	creds := c.host(host)
+	if creds.Headers == nil {
+		creds.Headers = make(map[string]string)
+	}
+	creds.Headers[http.CanonicalHeaderKey(strings.TrimSpace(name))] = strings.TrimSpace(value)
+	return nil
+}
+
+// HeadersFor returns the headers to send to u, nil if none are configured for its host
+func (c *Credentials) HeadersFor(u *url.URL) map[string]string {
+	if c == nil {
+		return nil
+	}
+	creds, ok := c.hosts[strings.ToLower(u.Hostname())]
+	if !ok {
+		return nil
+	}
+
+	headers := make(map[string]string, len(creds.Headers)+1)
+	for name, value := range creds.Headers {
+		headers[name] = value
+	}
+	if creds.BearerToken != "" {
+		headers["Authorization"] = "Bearer " + creds.BearerToken
+	} else if creds.Username != "" {
+		token := base64.StdEncoding.EncodeToString([]byte(creds.Username + ":" + creds.Password))
+		headers["Authorization"] = "Basic " + token
+	}
+	return headers
+}
+
+// HeaderNames lists every header the credentials can set, so they can be
+// stripped before a request is redirected to another host
+func (c *Credentials) HeaderNames() []string {
+	if c == nil {
+		return nil
+	}
+	seen := map[string]bool{"Authorization": true}
+	for _, creds := range c.hosts {
+		for name := range creds.Headers {
+			seen[name] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Apply sets the headers configured for req's host, removing any left over
+// from a previous host when following redirects
+func (c *Credentials) Apply(req *http.Request) {
+	if c == nil {
+		return
+	}
+	for _, name := range c.HeaderNames() {
+		req.Header.Del(name)
+	}
+	for name, value := range c.HeadersFor(req.URL) {
+		req.Header.Set(name, value)
+	}
+}
+
+// Fingerprint identifies the headers and cookies sent to u's host, empty when
+// none are. Results checked with credentials are only valid for the same ones.
+func (c *Credentials) Fingerprint(u *url.URL) string {
+	if c == nil {
+		return ""
+	}
+	var lines []string
+	for name, value := range c.HeadersFor(u) {
+		lines = append(lines, "header "+name+": "+value)
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, ck := range c.cookies {
+		if ck.matches(host) {
+			lines = append(lines, "cookie "+ck.Domain+ck.Path+" "+ck.Name+"="+ck.Value)
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	sort.Strings(lines)
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
+func (c *Credentials) host(host string) *HostCredentials {
+	host = strings.ToLower(host)
+	creds, ok := c.hosts[host]
+	if !ok {
+		creds = &HostCredentials{}
+		c.hosts[host] = creds
+	}
+	return creds
+}
+
+func splitHostSpec(spec string) (string, string, error) {
+	host, value, ok := strings.Cut(spec, "=")
+	host = strings.TrimSpace(host)
+	if !ok || host == "" {
+		return "", "", fmt.Errorf("%q must start with host=", spec)
+	}
+	return host, value, nil
+}
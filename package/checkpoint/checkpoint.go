@@ -0,0 +1,83 @@
+package checkpoint
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/MdSadiqMd/Scrape404/package/types"
+)
+
+const fileName = "checkpoint.json"
+
+type FrontierEntry struct {
+	URL   string `json:"url"`
+	Depth int    `json:"depth"`
+}
+
+type Checkpoint struct {
+	Config       types.ScanConfig     `json:"config"`
+	VisitedLinks map[string]bool      `json:"visitedLinks"`
+	DeadLinks    []types.DeadLink     `json:"deadLinks"`
+	VisitedPages int                  `json:"visitedPages"`
+	Frontier     []FrontierEntry      `json:"frontier"`
+	JSErrors     []types.JSError      `json:"jsErrors,omitempty"`
+	Evidence     []types.PageEvidence `json:"evidence,omitempty"`
+	Elapsed      int64                `json:"elapsedMs"`
+	Completed    bool                 `json:"completed"`
+	SavedAt      time.Time            `json:"savedAt"`
+}
+
+// NewScanID names a scan by its start time, with a random suffix so scans
+// started in the same second get their own results directory
+func NewScanID() string {
+	suffix := make([]byte, 3)
+	rand.Read(suffix)
+	return time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+}
+
+func Path(resultsDir, scanID string) string {
+	return filepath.Join(resultsDir, scanID, fileName)
+}
+
+func Save(cp *Checkpoint) error {
+	path := Path(cp.Config.ResultsDir, cp.Config.ScanID)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	cp.SavedAt = time.Now()
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temp file first so a crash mid-write never corrupts the last good checkpoint
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func Load(resultsDir, scanID string) (*Checkpoint, error) {
+	data, err := os.ReadFile(Path(resultsDir, scanID))
+	if err != nil {
+		return nil, fmt.Errorf("reading checkpoint for scan %s: %w", scanID, err)
+	}
+
+	cp := &Checkpoint{}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("parsing checkpoint for scan %s: %w", scanID, err)
+	}
+	if cp.VisitedLinks == nil {
+		cp.VisitedLinks = make(map[string]bool)
+	}
+	// Directory may have been moved since the checkpoint was written
+	cp.Config.ResultsDir = resultsDir
+	return cp, nil
+}
@@ -0,0 +1,179 @@
+package extract
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// maxTextLen caps the anchor or alt text kept for a link
+const maxTextLen = 100
+
+var simpleID = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// ElementText returns the anchor text of a link, or the alt text of an image.
+// Image links without text fall back to the alt of their first image.
+func ElementText(n *html.Node) string {
+	var text string
+	switch n.Data {
+	case "a":
+		var b strings.Builder
+		collectText(n, &b)
+		text = b.String()
+		if strings.TrimSpace(text) == "" {
+			if img := findElement(n, "img"); img != nil {
+				text = attr(img, "alt")
+			}
+		}
+	case "img", "area", "input":
+		text = attr(n, "alt")
+	}
+	return CleanText(text)
+}
+
+// CleanText collapses whitespace and truncates text to maxTextLen runes
+func CleanText(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > maxTextLen {
+		text = string(runes[:maxTextLen-1]) + "…"
+	}
+	return text
+}
+
+// CSSPath returns a selector for n, starting at the closest ancestor with an id.
+// The Playwright script builds the same selectors in the browser.
+func CSSPath(n *html.Node) string {
+	var parts []string
+	for ; n != nil && n.Type == html.ElementNode; n = n.Parent {
+		if id := attr(n, "id"); simpleID.MatchString(id) {
+			parts = append(parts, "#"+id)
+			break
+		}
+		part := n.Data
+		index, count := 0, 0
+		if n.Parent != nil {
+			for s := n.Parent.FirstChild; s != nil; s = s.NextSibling {
+				if s.Type == html.ElementNode && s.Data == n.Data {
+					count++
+					if s == n {
+						index = count
+					}
+				}
+			}
+		}
+		if count > 1 {
+			part += fmt.Sprintf(":nth-of-type(%d)", index)
+		}
+		parts = append(parts, part)
+	}
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, " > ")
+}
+
+// SourceLines finds the lines of elements in the HTML a document was parsed
+// from. The parser drops positions, so start tags are matched by tag name and
+// attribute value, or text for attribute-less rules, in document order.
+type SourceLines struct {
+	lines map[string][]int
+	used  map[string]int
+	nodes map[*html.Node]int
+}
+
+func NewSourceLines(data []byte) *SourceLines {
+	l := &SourceLines{lines: make(map[string][]int), used: make(map[string]int), nodes: make(map[*html.Node]int)}
+	z := html.NewTokenizer(bytes.NewReader(data))
+	line := 1
+	var open struct {
+		tag  string
+		line int
+	}
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return l
+		}
+		start := line
+		line += bytes.Count(z.Raw(), []byte("\n"))
+
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := z.Token()
+			open.tag, open.line = token.Data, start
+			for _, a := range token.Attr {
+				key := lineKey(token.Data, a.Key, a.Val)
+				l.lines[key] = append(l.lines[key], start)
+			}
+		case html.TextToken:
+			// Raw text of <style> and <script> matches goquery's Text()
+			if open.tag != "" {
+				key := lineKey(open.tag, "", string(z.Text()))
+				l.lines[key] = append(l.lines[key], open.line)
+			}
+			open.tag = ""
+		default:
+			open.tag = ""
+		}
+	}
+}
+
+// Line returns the line of n, whose attr holds value, or 0 when it is unknown
+func (l *SourceLines) Line(n *html.Node, attrName, value string) int {
+	if l == nil {
+		return 0
+	}
+	if line, ok := l.nodes[n]; ok {
+		return line
+	}
+	key := lineKey(n.Data, attrName, value)
+	lines := l.lines[key]
+	if l.used[key] >= len(lines) {
+		return 0
+	}
+	line := lines[l.used[key]]
+	l.used[key]++
+	l.nodes[n] = line
+	return line
+}
+
+func lineKey(tag, attrName, value string) string {
+	return tag + "\x00" + attrName + "\x00" + value
+}
+
+func attr(n *html.Node, name string) string {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func collectText(n *html.Node, b *strings.Builder) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode {
+			b.WriteString(c.Data)
+		} else if c.Type == html.ElementNode {
+			collectText(c, b)
+		}
+	}
+}
+
+func findElement(n *html.Node, tag string) *html.Node {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		if c.Data == tag {
+			return c
+		}
+		if found := findElement(c, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package extract
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	CSSURLType    = "css-url"
+	CSSImportType = "css-import"
+)
+
+var (
+	cssCommentPattern = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	cssImportPattern  = regexp.MustCompile(`(?i)@import\s+(?:url\(\s*)?(?:"([^"]*)"|'([^']*)'|([^\s;)'"]+))`)
+	cssURLPattern     = regexp.MustCompile(`(?i)url\(\s*(?:"([^"]*)"|'([^']*)'|([^)\s'"]*))\s*\)`)
+)
+
+// CSSRefs returns the @import targets and url() references of a stylesheet,
+// style block or style attribute
+func CSSRefs(css string) []Ref {
+	css = cssCommentPattern.ReplaceAllString(css, "")
+
+	var refs []Ref
+	imported := make(map[string]bool)
+	for _, m := range cssImportPattern.FindAllStringSubmatch(css, -1) {
+		if value := firstGroup(m); value != "" {
+			imported[value] = true
+			refs = append(refs, Ref{Value: value, Type: CSSImportType, Stylesheet: true})
+		}
+	}
+	for _, m := range cssURLPattern.FindAllStringSubmatch(css, -1) {
+		// @import url(...) was already picked up above
+		if value := firstGroup(m); value != "" && !imported[value] {
+			refs = append(refs, Ref{Value: value, Type: CSSURLType})
+		}
+	}
+	return refs
+}
+
+func firstGroup(m []string) string {
+	for _, group := range m[1:] {
+		if group = strings.TrimSpace(group); group != "" {
+			return group
+		}
+	}
+	return ""
+}
@@ -0,0 +1,31 @@
+package extract
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCSSRefs(t *testing.T) {
+	tests := []struct {
+		name string
+		css  string
+		want []Ref
+	}{
+		{"unquoted url", "body { background: url(bg.png) }", []Ref{{Value: "bg.png", Type: CSSURLType}}},
+		{"quoted urls", `a { background: url("a.png") } b { background: url( 'b.png' ) }`, []Ref{{Value: "a.png", Type: CSSURLType}, {Value: "b.png", Type: CSSURLType}}},
+		{"font sources", `@font-face { src: url(f.woff2) format("woff2"), url(f.woff) format("woff") }`, []Ref{{Value: "f.woff2", Type: CSSURLType}, {Value: "f.woff", Type: CSSURLType}}},
+		{"empty url", "a { background: url() }", nil},
+		{"import string", `@import "base.css";`, []Ref{{Value: "base.css", Type: CSSImportType, Stylesheet: true}}},
+		{"import url", `@import url('theme.css') screen;`, []Ref{{Value: "theme.css", Type: CSSImportType, Stylesheet: true}}},
+		{"import unquoted url", "@IMPORT url(print.css) print;", []Ref{{Value: "print.css", Type: CSSImportType, Stylesheet: true}}},
+		{"commented out", "/* url(old.png) @import 'old.css'; */ a { background: url(new.png) }", []Ref{{Value: "new.png", Type: CSSURLType}}},
+		{"style attribute", "background-image:url(/img/hero.jpg)", []Ref{{Value: "/img/hero.jpg", Type: CSSURLType}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CSSRefs(tt.css); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CSSRefs(%q) = %v, want %v", tt.css, got, tt.want)
+			}
+		})
+	}
+}
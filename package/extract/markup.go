@@ -0,0 +1,176 @@
+package extract
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// SourceLink is a reference found in a Markdown or reStructuredText source file
+type SourceLink struct {
+	Value string
+	Type  string
+	Line  int
+}
+
+const (
+	SourceLinkType  = "link"
+	SourceImageType = "image"
+)
+
+var (
+	mdInline = regexp.MustCompile(`(!?)\[(?:[^\[\]]|\[[^\[\]]*\])*\]\(\s*(<[^>]*>|[^()\s]+(?:\([^()\s]*\)[^()\s]*)*)(?:\s+(?:"[^"]*"|'[^']*'|\([^)]*\)))?\s*\)`)
+	// Labels starting with ^ are GFM footnote definitions, not links
+	mdReference = regexp.MustCompile(`^ {0,3}\[[^\]^][^\]]*\]:\s*(<[^>]*>|\S+)`)
+	mdAutolink  = regexp.MustCompile(`<((?:https?|ftp)://[^>\s]+|mailto:[^>\s]+)>`)
+	mdCodeSpan  = regexp.MustCompile("`+[^`]*`+")
+	htmlRef     = regexp.MustCompile(`(?i)<(a|img|source|video|audio|iframe)\b[^>]*?\s(href|src)\s*=\s*("[^"]*"|'[^']*')`)
+	bareURL     = regexp.MustCompile(`(?:https?|ftp)://[^\s<>"'\x60\[\]]+`)
+
+	rstInline = regexp.MustCompile("`[^`]*<([^<>`]+)>`__?")
+	rstTarget = regexp.MustCompile(`^\s*\.\. _[^:]+:\s*(\S+)\s*$`)
+	rstImage  = regexp.MustCompile(`^\s*\.\. (?:image|figure)::\s*(\S+)`)
+)
+
+// MarkdownLinks returns inline links and images, reference definitions,
+// autolinks, bare URLs and HTML href/src attributes outside code and comments
+func MarkdownLinks(data []byte) []SourceLink {
+	var links []SourceLink
+	fence := ""
+	inComment := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Text()
+		if inComment {
+			end := strings.Index(line, "-->")
+			if end < 0 {
+				continue
+			}
+			line = line[end+len("-->"):]
+			inComment = false
+		}
+		trimmed := strings.TrimSpace(line)
+
+		// Fenced code blocks end at a fence of the same character
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		if strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+
+		line = mdCodeSpan.ReplaceAllString(line, "")
+		line, inComment = stripHTMLComments(line)
+		add := func(value, linkType string) {
+			value = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(value, "<"), ">"))
+			if value != "" {
+				links = append(links, SourceLink{Value: value, Type: linkType, Line: lineNum})
+			}
+		}
+
+		if m := mdReference.FindStringSubmatch(line); m != nil {
+			add(m[1], SourceLinkType)
+			continue
+		}
+		for _, m := range mdInline.FindAllStringSubmatch(line, -1) {
+			if m[1] == "!" {
+				add(m[2], SourceImageType)
+			} else {
+				add(m[2], SourceLinkType)
+			}
+		}
+		line = mdInline.ReplaceAllString(line, "")
+		for _, m := range mdAutolink.FindAllStringSubmatch(line, -1) {
+			add(m[1], SourceLinkType)
+		}
+		line = mdAutolink.ReplaceAllString(line, "")
+		for _, m := range htmlRef.FindAllStringSubmatch(line, -1) {
+			linkType := SourceLinkType
+			if !strings.EqualFold(m[1], "a") {
+				linkType = SourceImageType
+			}
+			add(m[3][1:len(m[3])-1], linkType)
+		}
+		line = htmlRef.ReplaceAllString(line, "")
+		for _, u := range bareURL.FindAllString(line, -1) {
+			add(trimURLPunctuation(u), SourceLinkType)
+		}
+	}
+	return links
+}
+
+// RSTLinks returns inline hyperlinks, hyperlink targets, image and figure
+// directives and standalone URIs
+func RSTLinks(data []byte) []SourceLink {
+	var links []SourceLink
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Text()
+		add := func(value, linkType string) {
+			if value = strings.TrimSpace(value); value != "" {
+				links = append(links, SourceLink{Value: value, Type: linkType, Line: lineNum})
+			}
+		}
+
+		if m := rstImage.FindStringSubmatch(line); m != nil {
+			add(m[1], SourceImageType)
+			continue
+		}
+		if m := rstTarget.FindStringSubmatch(line); m != nil {
+			add(m[1], SourceLinkType)
+			continue
+		}
+		for _, m := range rstInline.FindAllStringSubmatch(line, -1) {
+			add(m[1], SourceLinkType)
+		}
+		line = rstInline.ReplaceAllString(line, "")
+		for _, u := range bareURL.FindAllString(line, -1) {
+			add(trimURLPunctuation(u), SourceLinkType)
+		}
+	}
+	return links
+}
+
+// stripHTMLComments removes <!-- ... --> spans from line and reports whether
+// a comment is still open at its end
+func stripHTMLComments(line string) (string, bool) {
+	var b strings.Builder
+	for {
+		start := strings.Index(line, "<!--")
+		if start < 0 {
+			b.WriteString(line)
+			return b.String(), false
+		}
+		b.WriteString(line[:start])
+		end := strings.Index(line[start+len("<!--"):], "-->")
+		if end < 0 {
+			return b.String(), true
+		}
+		line = line[start+len("<!--")+end+len("-->"):]
+	}
+}
+
+// trimURLPunctuation drops sentence punctuation and unbalanced closing
+// parentheses that follow a bare URL in prose
+func trimURLPunctuation(u string) string {
+	for {
+		trimmed := strings.TrimRight(u, ".,;:!?*_")
+		if strings.HasSuffix(trimmed, ")") && strings.Count(trimmed, "(") < strings.Count(trimmed, ")") {
+			trimmed = trimmed[:len(trimmed)-1]
+		}
+		if trimmed == u {
+			return u
+		}
+		u = trimmed
+	}
+}
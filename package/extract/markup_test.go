@@ -0,0 +1,58 @@
+package extract
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMarkdownLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []SourceLink
+	}{
+		{"inline link", "See [guide](guide/intro.md).", []SourceLink{{"guide/intro.md", SourceLinkType, 1}}},
+		{"inline link with title", `[a](<b c.md> "Title")`, []SourceLink{{"b c.md", SourceLinkType, 1}}},
+		{"image", "![logo](img/logo.png)", []SourceLink{{"img/logo.png", SourceImageType, 1}}},
+		{"reference definition", "text\n[ref]: https://example.com/a", []SourceLink{{"https://example.com/a", SourceLinkType, 2}}},
+		{"footnote definition", "[^1]: Some explanation", nil},
+		{"autolink", "<https://example.com>", []SourceLink{{"https://example.com", SourceLinkType, 1}}},
+		{"mailto autolink", "<mailto:hi@example.com>", []SourceLink{{"mailto:hi@example.com", SourceLinkType, 1}}},
+		{"bare URL", "Visit https://example.com/x.", []SourceLink{{"https://example.com/x", SourceLinkType, 1}}},
+		{"HTML image", `<img alt="x" src="a.png">`, []SourceLink{{"a.png", SourceImageType, 1}}},
+		{"code span", "`[x](no.md)` and [y](yes.md)", []SourceLink{{"yes.md", SourceLinkType, 1}}},
+		{"fenced code", "```\n[x](no.md)\n```\n[y](yes.md)", []SourceLink{{"yes.md", SourceLinkType, 4}}},
+		{"indented code", "    [x](no.md)", nil},
+		{"HTML comment", "<!-- [x](broken.md) --> [y](yes.md)", []SourceLink{{"yes.md", SourceLinkType, 1}}},
+		{"multi-line HTML comment", "<!--\n[x](broken.md)\n-->\n[y](yes.md)", []SourceLink{{"yes.md", SourceLinkType, 4}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MarkdownLinks([]byte(tt.src)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MarkdownLinks(%q) = %v, want %v", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRSTLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []SourceLink
+	}{
+		{"inline link", "See `the guide <guide.rst>`_.", []SourceLink{{"guide.rst", SourceLinkType, 1}}},
+		{"anonymous link", "`x <https://example.com>`__", []SourceLink{{"https://example.com", SourceLinkType, 1}}},
+		{"target", ".. _docs: https://example.com/docs", []SourceLink{{"https://example.com/docs", SourceLinkType, 1}}},
+		{"image", "\n.. image:: img/logo.png", []SourceLink{{"img/logo.png", SourceImageType, 2}}},
+		{"figure", ".. figure:: fig.svg", []SourceLink{{"fig.svg", SourceImageType, 1}}},
+		{"bare URL", "Go to https://example.com/a).", []SourceLink{{"https://example.com/a", SourceLinkType, 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RSTLinks([]byte(tt.src)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RSTLinks(%q) = %v, want %v", tt.src, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package extract
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// RefreshURL returns the target of a meta refresh content value such as
+// "5; url=/next", empty when the page only reloads itself
+func RefreshURL(content string) string {
+	_, target, ok := strings.Cut(content, ";")
+	if !ok {
+		if _, target, ok = strings.Cut(content, ","); !ok {
+			return ""
+		}
+	}
+
+	target = strings.TrimSpace(target)
+	if len(target) >= 3 && strings.EqualFold(target[:3], "url") {
+		rest := strings.TrimSpace(target[3:])
+		if strings.HasPrefix(rest, "=") {
+			target = strings.TrimSpace(rest[1:])
+		}
+	}
+	return strings.Trim(target, `"'`)
+}
+
+// JSONLDURLs returns every absolute http(s) URL among the string values of a
+// JSON-LD block, leaving out @context vocabulary references
+func JSONLDURLs(data string) []string {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(data), &doc); err != nil {
+		return nil
+	}
+
+	var urls []string
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for k, child := range v {
+				if k != "@context" {
+					walk(child)
+				}
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		case string:
+			lower := strings.ToLower(v)
+			if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") {
+				urls = append(urls, v)
+			}
+		}
+	}
+	walk(doc)
+	return urls
+}
@@ -0,0 +1,54 @@
+package extract
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestRefreshURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"delay and url", "5; url=/next", "/next"},
+		{"no space", "0;URL=https://example.com/", "https://example.com/"},
+		{"quoted url", `3; url='/quoted page'`, "/quoted page"},
+		{"spaces around equals", "1 ; url = /spaced", "/spaced"},
+		{"comma separator", "0, /comma", "/comma"},
+		{"url without url=", "0; /bare", "/bare"},
+		{"reload only", "30", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RefreshURL(tt.content); got != tt.want {
+				t.Errorf("RefreshURL(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONLDURLs(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{"flat object", `{"@context": "https://schema.org", "@type": "Organization", "url": "https://example.com", "logo": "https://example.com/logo.png"}`, []string{"https://example.com", "https://example.com/logo.png"}},
+		{"nested and arrays", `{"@graph": [{"sameAs": ["https://a.example", "HTTP://b.example"]}, {"image": {"url": "https://c.example/i.jpg"}}]}`, []string{"HTTP://b.example", "https://a.example", "https://c.example/i.jpg"}},
+		{"context object", `{"@context": {"@vocab": "https://schema.org/"}, "name": "No links"}`, nil},
+		{"relative and other schemes", `{"url": "/about", "email": "mailto:hi@example.com"}`, nil},
+		{"invalid JSON", `{"url": "https://example.com"`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := JSONLDURLs(tt.data)
+			// Object keys are walked in map order
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("JSONLDURLs(%q) = %q, want %q", tt.data, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package extract
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"mime"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// PDFLinkType is reported for URI annotations found inside PDF documents
+const PDFLinkType = "pdf-link"
+
+var (
+	// A stream's dictionary ends right before its stream keyword
+	pdfStreamPattern = regexp.MustCompile(`>>\s*stream\r?\n`)
+	pdfURIPattern    = regexp.MustCompile(`/URI\s*(\(|<[0-9A-Fa-f\s]*>)`)
+)
+
+// Limits on decompression, so a small crafted PDF can't expand without bound
+// through one huge stream or many small ones
+const (
+	maxInflatedStream = 16 << 20
+	maxInflatedTotal  = 64 << 20
+)
+
+// IsPDF reports whether link points at a PDF by its path extension. Callers
+// confirm the downloaded document with IsPDFContent.
+func IsPDF(link string) bool {
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	return strings.HasSuffix(strings.ToLower(u.Path), ".pdf")
+}
+
+// IsPDFContent reports whether a response is a PDF, by its Content-Type or
+// the %PDF- header readers accept within the first kilobyte
+func IsPDFContent(contentType string, data []byte) bool {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && mediaType == "application/pdf" {
+		return true
+	}
+	return bytes.Contains(data[:min(len(data), 1024)], []byte("%PDF-"))
+}
+
+// PDFLinks returns the targets of the URI actions in a PDF. Besides the plain
+// file it searches Flate compressed streams, which is where PDF 1.5+ object
+// streams keep their annotations.
+func PDFLinks(data []byte, pdfURL string) []string {
+	if !IsPDFContent("", data) {
+		return nil
+	}
+
+	chunks := [][]byte{data}
+	budget := int64(maxInflatedTotal)
+	for _, loc := range pdfStreamPattern.FindAllIndex(data, -1) {
+		if budget <= 0 {
+			break
+		}
+		dict := pdfDictBefore(data, loc[0]+len(">>"))
+		if !bytes.Contains(dict, []byte("/FlateDecode")) {
+			continue
+		}
+		start := loc[1]
+		end := bytes.Index(data[start:], []byte("endstream"))
+		if end < 0 {
+			continue
+		}
+		if inflated := inflate(data[start:start+end], min(budget, maxInflatedStream)); len(inflated) > 0 {
+			budget -= int64(len(inflated))
+			chunks = append(chunks, inflated)
+		}
+	}
+
+	base, _ := url.Parse(pdfURL)
+	seen := make(map[string]bool)
+	var links []string
+	for _, chunk := range chunks {
+		for _, loc := range pdfURIPattern.FindAllSubmatchIndex(chunk, -1) {
+			var target string
+			if chunk[loc[2]] == '(' {
+				target = pdfLiteralString(chunk[loc[2]+1:])
+			} else {
+				target = pdfHexString(chunk[loc[2]+1 : loc[3]-1])
+			}
+			target = strings.TrimSpace(target)
+			if base != nil {
+				target = Resolve(base, target)
+			}
+			if target != "" && !seen[target] {
+				seen[target] = true
+				links = append(links, target)
+			}
+		}
+	}
+	return links
+}
+
+// pdfDictBefore returns the dictionary ending at end, which is just past its
+// closing >>. Nested dictionaries such as /DecodeParms are kept whole.
+func pdfDictBefore(data []byte, end int) []byte {
+	depth := 0
+	for i := end - 2; i >= 0; i-- {
+		switch {
+		case i+1 < len(data) && data[i] == '>' && data[i+1] == '>':
+			depth++
+			i--
+		case data[i] == '<' && i+1 < end && data[i+1] == '<':
+			depth--
+			if depth == 0 {
+				return data[i:end]
+			}
+		}
+	}
+	return nil
+}
+
+func inflate(stream []byte, limit int64) []byte {
+	r, err := zlib.NewReader(bytes.NewReader(stream))
+	if err != nil {
+		return nil
+	}
+	defer r.Close()
+	// Streams are often followed by padding zlib complains about, keep what was read
+	out, _ := io.ReadAll(io.LimitReader(r, limit))
+	return out
+}
+
+// pdfLiteralString decodes a (...) string starting right after the opening parenthesis
+func pdfLiteralString(b []byte) string {
+	var out []byte
+	depth := 0
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case c == '\\' && i+1 < len(b):
+			i++
+			switch b[i] {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case '\r', '\n':
+				// Line continuation
+			default:
+				if b[i] >= '0' && b[i] <= '7' {
+					n := 0
+					j := i
+					for ; j < len(b) && j < i+3 && b[j] >= '0' && b[j] <= '7'; j++ {
+						n = n*8 + int(b[j]-'0')
+					}
+					out = append(out, byte(n))
+					i = j - 1
+				} else {
+					out = append(out, b[i])
+				}
+			}
+		case c == '(':
+			depth++
+			out = append(out, c)
+		case c == ')':
+			if depth == 0 {
+				return string(out)
+			}
+			depth--
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+	return string(out)
+}
+
+func pdfHexString(b []byte) string {
+	var digits []byte
+	for _, c := range b {
+		if !strings.ContainsRune(" \t\r\n", rune(c)) {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+
+	out := make([]byte, len(digits)/2)
+	for i := range out {
+		out[i] = unhex(digits[2*i])<<4 | unhex(digits[2*i+1])
+	}
+	return string(out)
+}
+
+func unhex(c byte) byte {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0'
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10
+	}
+	return 0
+}
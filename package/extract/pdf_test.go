@@ -0,0 +1,75 @@
+package extract
+
+import (
+	"bytes"
+	"compress/zlib"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func deflate(t *testing.T, data string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	if _, err := w.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	return buf.String()
+}
+
+func TestPDFLinks(t *testing.T) {
+	// Repeated so deflate compresses it rather than storing it as is
+	annot := strings.Repeat("<< /Type /Annot /A << /S /URI /URI (https://example.com/packed) >> >>\n", 20)
+	tests := []struct {
+		name string
+		pdf  string
+		want []string
+	}{
+		{"literal URI", "%PDF-1.4\n<< /A << /S /URI /URI (https://example.com/a) >> >>", []string{"https://example.com/a"}},
+		{"escaped literal URI", `%PDF-1.4 /URI (https://example.com/a\(1\))`, []string{"https://example.com/a(1)"}},
+		{"hex URI", "%PDF-1.4 /URI <68747470733A2F2F6578616D706C652E636F6D2F68>", []string{"https://example.com/h"}},
+		{"relative URI", "%PDF-1.4 /URI (other.html)", []string{"https://example.com/docs/other.html"}},
+		{"duplicate URIs", "%PDF-1.4 /URI (https://example.com/a) /URI (https://example.com/a)", []string{"https://example.com/a"}},
+		{"flate stream", "%PDF-1.5\n1 0 obj << /Filter /FlateDecode /Length 9 >>\nstream\n" + deflate(t, annot) + "\nendstream", []string{"https://example.com/packed"}},
+		{"flate stream with nested dictionary", "%PDF-1.5\n1 0 obj << /Filter /FlateDecode /DecodeParms << /Columns 4 >> /Length 9 >>\nstream\n" + deflate(t, annot) + "\nendstream", []string{"https://example.com/packed"}},
+		{"not a PDF", "<html> /URI (https://example.com/a)", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PDFLinks([]byte(tt.pdf), "https://example.com/docs/file.pdf"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PDFLinks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPDFContent(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		data        string
+		want        bool
+	}{
+		{"content type", "application/pdf", "", true},
+		{"content type with parameters", "application/pdf; qs=0.001", "", true},
+		{"magic bytes", "application/octet-stream", "%PDF-1.7\n", true},
+		{"magic bytes after junk", "", "\xef\xbb\xbf  %PDF-1.4", true},
+		{"HTML", "text/html; charset=utf-8", "<!doctype html>", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPDFContent(tt.contentType, []byte(tt.data)); got != tt.want {
+				t.Errorf("IsPDFContent(%q, %q) = %v, want %v", tt.contentType, tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInflateLimit(t *testing.T) {
+	stream := deflate(t, string(bytes.Repeat([]byte("a"), 1<<16)))
+	if got := inflate([]byte(stream), 100); len(got) != 100 {
+		t.Errorf("inflate() returned %d bytes, want 100", len(got))
+	}
+}
@@ -0,0 +1,44 @@
+package extract
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// spaRoots are the mount points of common client-side frameworks, empty until
+// their JavaScript runs
+var spaRoots = []string{
+	"#root", "#app", "#__next", "#__nuxt", "#___gatsby", "#svelte",
+	"app-root", "[data-reactroot]", "[ng-app]", "[ng-version]",
+}
+
+// RenderReason inspects a statically fetched document and returns why it looks
+// incomplete without JavaScript, or an empty string when it looks complete
+func RenderReason(doc *goquery.Selection) string {
+	body := doc.Find("body")
+
+	// Text outside scripts, styles and noscript is what a reader would see
+	visible := body.Clone()
+	visible.Find("script, style, noscript, template").Remove()
+	if strings.TrimSpace(visible.Text()) == "" && visible.Find("a[href], img[src]").Length() == 0 {
+		return "empty body"
+	}
+
+	for _, selector := range spaRoots {
+		root := body.Find(selector).First()
+		if root.Length() > 0 && root.Children().Length() == 0 && strings.TrimSpace(root.Text()) == "" {
+			return "empty " + selector + " app root"
+		}
+	}
+
+	reason := ""
+	body.Find("noscript").EachWithBreak(func(_ int, n *goquery.Selection) bool {
+		if strings.Contains(strings.ToLower(n.Text()), "javascript") {
+			reason = "noscript JavaScript warning"
+			return false
+		}
+		return true
+	})
+	return reason
+}
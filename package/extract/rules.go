@@ -0,0 +1,268 @@
+package extract
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Format says how a rule's value is turned into URL references
+type Format int
+
+const (
+	FormatURL Format = iota
+	FormatSrcset
+	FormatCSS
+	FormatRefresh
+	FormatJSONLD
+)
+
+type Rule struct {
+	Selector string
+	// Attr is the attribute holding the reference, empty for the element's text
+	Attr   string
+	Type   string
+	Format Format
+	// Crawl marks links whose same-site targets are visited as pages
+	Crawl bool
+}
+
+// Ref is one URL reference found in an element, before resolution
+type Ref struct {
+	Value string
+	Type  string
+	// Stylesheet marks targets whose contents are downloaded and checked too
+	Stylesheet bool
+}
+
+// Rules is the extractor table shared by the colly and Playwright engines.
+// Selectors must work in both goquery and document.querySelectorAll.
+var Rules = []Rule{
+	{Selector: "a[href]", Attr: "href", Type: "link", Crawl: true},
+	{Selector: "img[src]", Attr: "src", Type: "image"},
+	{Selector: "img[srcset]", Attr: "srcset", Type: "srcset", Format: FormatSrcset},
+	{Selector: "picture source[srcset]", Attr: "srcset", Type: "picture-source", Format: FormatSrcset},
+	{Selector: "video[src], video source[src]", Attr: "src", Type: "video"},
+	{Selector: "audio[src], audio source[src]", Attr: "src", Type: "audio"},
+	{Selector: "iframe[src]", Attr: "src", Type: "iframe"},
+	{Selector: "object[data]", Attr: "data", Type: "object"},
+	{Selector: "embed[src]", Attr: "src", Type: "embed"},
+	{Selector: "link[rel~=stylesheet][href]", Attr: "href", Type: "css"},
+	{Selector: "link[rel~=icon][href]", Attr: "href", Type: "favicon"},
+	{Selector: "link[rel~=apple-touch-icon][href], link[rel~=apple-touch-icon-precomposed][href]", Attr: "href", Type: "touch-icon"},
+	{Selector: "link[rel~=manifest][href]", Attr: "href", Type: "manifest"},
+	{Selector: "link[rel~=preload][href], link[rel~=modulepreload][href]", Attr: "href", Type: "preload"},
+	{Selector: "link[rel~=prefetch][href]", Attr: "href", Type: "prefetch"},
+	{Selector: "script[src]", Attr: "src", Type: "script"},
+	{Selector: "style", Type: CSSURLType, Format: FormatCSS},
+	{Selector: "[style]", Attr: "style", Type: CSSURLType, Format: FormatCSS},
+	{Selector: `meta[property="og:image"][content], meta[property="og:image:url"][content], meta[property="og:image:secure_url"][content], meta[property="og:video"][content], meta[property="og:video:url"][content], meta[property="og:video:secure_url"][content], meta[property="og:audio"][content], meta[property="og:audio:url"][content], meta[property="og:audio:secure_url"][content], meta[property="og:url"][content]`, Attr: "content", Type: "og-meta"},
+	{Selector: `meta[name="twitter:image"][content], meta[name="twitter:image:src"][content], meta[name="twitter:player"][content], meta[name="twitter:player:stream"][content], meta[property="twitter:image"][content]`, Attr: "content", Type: "twitter-meta"},
+	{Selector: "link[rel~=canonical][href]", Attr: "href", Type: "canonical"},
+	{Selector: "link[rel~=alternate][hreflang][href]", Attr: "href", Type: "hreflang"},
+	{Selector: "link[rel~=alternate][href]:not([hreflang])", Attr: "href", Type: "alternate"},
+	{Selector: `meta[http-equiv="refresh"][content], meta[http-equiv="Refresh"][content], meta[http-equiv="REFRESH"][content]`, Attr: "content", Type: "meta-refresh", Format: FormatRefresh, Crawl: true},
+	{Selector: `script[type="application/ld+json"]`, Type: "json-ld", Format: FormatJSONLD},
+}
+
+// ManifestIconType is reported for icons listed inside a web app manifest
+const ManifestIconType = "manifest-icon"
+
+// Refs splits an attribute value or element text into the references it contains
+func (r Rule) Refs(value string) []Ref {
+	var values []string
+	switch r.Format {
+	case FormatCSS:
+		return CSSRefs(value)
+	case FormatSrcset:
+		values = ParseSrcset(value)
+	case FormatRefresh:
+		values = []string{RefreshURL(value)}
+	case FormatJSONLD:
+		values = JSONLDURLs(value)
+	default:
+		values = []string{strings.TrimSpace(value)}
+	}
+
+	var refs []Ref
+	for _, v := range values {
+		if v != "" {
+			refs = append(refs, Ref{Value: v, Type: r.Type, Stylesheet: r.Type == "css"})
+		}
+	}
+	return refs
+}
+
+// Skip reports links that can't be checked. Other schemes than HTTP, such as
+// mailto: and data:, go to the checker registered for them.
+func Skip(link string) bool {
+	return link == "" || strings.HasPrefix(strings.ToLower(link), "javascript:")
+}
+
+// Resolve makes ref absolute against base, dropping fragments like colly's AbsoluteURL
+func Resolve(base *url.URL, ref string) string {
+	if strings.HasPrefix(ref, "#") {
+		return ""
+	}
+	abs, err := base.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	abs.Fragment = ""
+	return abs.String()
+}
+
+// ParseSrcset returns the URLs of a srcset attribute, ignoring width and density descriptors
+func ParseSrcset(srcset string) []string {
+	var urls []string
+	s := srcset
+	for {
+		s = strings.TrimLeft(s, " \t\n\r\f,")
+		if s == "" {
+			return urls
+		}
+
+		end := strings.IndexAny(s, " \t\n\r\f")
+		if end < 0 {
+			end = len(s)
+		}
+		candidate := s[:end]
+		s = s[end:]
+
+		// A URL directly followed by a comma has no descriptors
+		if trimmed := strings.TrimRight(candidate, ","); trimmed != candidate {
+			urls = append(urls, trimmed)
+			continue
+		}
+		urls = append(urls, candidate)
+
+		// Skip descriptors up to the next comma outside parentheses
+		depth := 0
+		i := 0
+		for ; i < len(s); i++ {
+			if s[i] == '(' {
+				depth++
+			} else if s[i] == ')' && depth > 0 {
+				depth--
+			} else if s[i] == ',' && depth == 0 {
+				break
+			}
+		}
+		s = s[i:]
+	}
+}
+
+// ManifestIcons returns the absolute icon URLs of a web app manifest
+func ManifestIcons(data []byte, manifestURL string) ([]string, error) {
+	var manifest struct {
+		Icons []struct {
+			Src string `json:"src"`
+		} `json:"icons"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %w", err)
+	}
+
+	base, err := url.Parse(manifestURL)
+	if err != nil {
+		return nil, err
+	}
+	var icons []string
+	for _, icon := range manifest.Icons {
+		if link := Resolve(base, strings.TrimSpace(icon.Src)); !Skip(link) {
+			icons = append(icons, link)
+		}
+	}
+	return icons, nil
+}
+
+// PlaywrightScript returns a page.Evaluate function that collects the raw
+// attribute values for every rule as [ruleIndex, value, context] triples, the
+// context matching ElementText and CSSPath. Open shadow roots are searched with
+// their host document and every same-origin iframe is reported as a frame of its
+// own, top-level document first.
+func PlaywrightScript() string {
+	rules := make([][2]string, len(Rules))
+	for i, rule := range Rules {
+		rules[i] = [2]string{rule.Selector, rule.Attr}
+	}
+	encoded, _ := json.Marshal(rules)
+
+	return `() => {
+		const rules = ` + string(encoded) + `;
+		const frames = [];
+		const maxTextLen = ` + fmt.Sprint(maxTextLen) + `;
+		const cleanText = text => {
+			const chars = Array.from((text || '').trim().split(/\s+/).join(' '));
+			return chars.length > maxTextLen ? chars.slice(0, maxTextLen - 1).join('') + '…' : chars.join('');
+		};
+		const elementText = el => {
+			const tag = el.tagName.toLowerCase();
+			if (tag === 'a') {
+				let text = el.textContent;
+				if (!text.trim()) {
+					const img = el.querySelector('img');
+					text = img ? img.getAttribute('alt') : '';
+				}
+				return cleanText(text);
+			}
+			if (tag === 'img' || tag === 'area' || tag === 'input') {
+				return cleanText(el.getAttribute('alt'));
+			}
+			return '';
+		};
+		const cssPath = el => {
+			const parts = [];
+			for (; el && el.nodeType === 1; el = el.parentNode) {
+				const id = el.getAttribute('id');
+				if (id && /^[A-Za-z][A-Za-z0-9_-]*$/.test(id)) {
+					parts.unshift('#' + id);
+					break;
+				}
+				const tag = el.tagName.toLowerCase();
+				const siblings = el.parentNode ? Array.from(el.parentNode.children).filter(s => s.tagName === el.tagName) : [el];
+				parts.unshift(siblings.length > 1 ? tag + ':nth-of-type(' + (siblings.indexOf(el) + 1) + ')' : tag);
+			}
+			return parts.join(' > ');
+		};
+		const collectRoot = (root, items, iframes) => {
+			rules.forEach(([selector, attr], i) => {
+				root.querySelectorAll(selector).forEach(el => {
+					const value = attr ? el.getAttribute(attr) : el.textContent;
+					if (value) {
+						items.push([i, value, { text: elementText(el), selector: cssPath(el), tag: el.tagName.toLowerCase() }]);
+					}
+				});
+			});
+			root.querySelectorAll('*').forEach(el => {
+				if (el.shadowRoot) {
+					collectRoot(el.shadowRoot, items, iframes);
+				}
+				if (el.tagName === 'IFRAME' || el.tagName === 'FRAME') {
+					iframes.push(el);
+				}
+			});
+		};
+		const collectDocument = (doc, parentURL) => {
+			const items = [];
+			const iframes = [];
+			const href = doc.location ? doc.location.href : '';
+			const url = href.startsWith('http') ? href : parentURL;
+			collectRoot(doc, items, iframes);
+			frames.push({ url: url, baseURI: doc.baseURI, items: items });
+			iframes.forEach(frame => {
+				let child = null;
+				try {
+					child = frame.contentDocument;
+				} catch (e) {}
+				// Cross-origin frames have no readable document
+				if (child && child.documentElement) {
+					collectDocument(child, url);
+				}
+			});
+		};
+		collectDocument(document, location.href);
+		return { frames: frames };
+	}`
+}
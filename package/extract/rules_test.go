@@ -0,0 +1,57 @@
+package extract
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSrcset(t *testing.T) {
+	tests := []struct {
+		name   string
+		srcset string
+		want   []string
+	}{
+		{"single URL", "a.png", []string{"a.png"}},
+		{"width descriptors", "a.png 480w, b.png 800w", []string{"a.png", "b.png"}},
+		{"density descriptors", "a.png 1x,b.png 2x", []string{"a.png", "b.png"}},
+		{"no descriptors", "a.png, b.png", []string{"a.png", "b.png"}},
+		{"comma without whitespace", "a.png,b.png", []string{"a.png,b.png"}},
+		{"extra whitespace", "\n  a.png  1x ,\n  b.png 2x\n", []string{"a.png", "b.png"}},
+		{"comma inside URL", "img.php?w=1,2 1x, b.png 2x", []string{"img.php?w=1,2", "b.png"}},
+		{"absolute URLs", "https://cdn.example.com/a.png 1x, //cdn.example.com/b.png 2x", []string{"https://cdn.example.com/a.png", "//cdn.example.com/b.png"}},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseSrcset(tt.srcset); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSrcset(%q) = %q, want %q", tt.srcset, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManifestIcons(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     []string
+		wantErr  bool
+	}{
+		{"relative icons", `{"icons": [{"src": "icons/192.png", "sizes": "192x192"}, {"src": "/512.png"}]}`, []string{"https://example.com/app/icons/192.png", "https://example.com/512.png"}, false},
+		{"absolute icon", `{"icons": [{"src": " https://cdn.example.com/i.png "}]}`, []string{"https://cdn.example.com/i.png"}, false},
+		{"data URI icon", `{"icons": [{"src": "data:image/png;base64,AAAA"}]}`, []string{"data:image/png;base64,AAAA"}, false},
+		{"no icons", `{"name": "App"}`, nil, false},
+		{"invalid JSON", `{"icons": [`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ManifestIcons([]byte(tt.manifest), "https://example.com/app/manifest.json")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ManifestIcons() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ManifestIcons() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package network
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+type ClientCert struct {
+	CertFile string
+	KeyFile  string
+}
+
+// Config describes how outgoing connections reach the network. It is shared by
+// the colly collector, the Playwright browser and CheckLink.
+type Config struct {
+	ProxyURL           string
+	NoProxy            []string
+	CAFiles            []string
+	ClientCerts        map[string]ClientCert
+	InsecureSkipVerify bool
+}
+
+// AddClientCert parses "host=cert.pem:key.pem"
+func (c *Config) AddClientCert(spec string) error {
+	host, files, ok := strings.Cut(spec, "=")
+	certFile, keyFile, ok2 := strings.Cut(files, ":")
+	if !ok || !ok2 || host == "" || certFile == "" || keyFile == "" {
+		return fmt.Errorf("client cert %q must look like host=cert.pem:key.pem", spec)
+	}
+	if c.ClientCerts == nil {
+		c.ClientCerts = make(map[string]ClientCert)
+	}
+	c.ClientCerts[strings.ToLower(host)] = ClientCert{CertFile: certFile, KeyFile: keyFile}
+	return nil
+}
+
+// AddNoProxy parses a comma-separated list of hosts, ".domain" suffixes or "*"
+func (c *Config) AddNoProxy(list string) error {
+	for _, host := range strings.Split(list, ",") {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			c.NoProxy = append(c.NoProxy, host)
+		}
+	}
+	return nil
+}
+
+func (c *Config) AddCAFile(path string) error {
+	c.CAFiles = append(c.CAFiles, path)
+	return nil
+}
+
+// BypassProxy reports whether requests to host go direct
+func (c *Config) BypassProxy(host string) bool {
+	host = strings.ToLower(host)
+	for _, rule := range c.NoProxy {
+		switch {
+		case rule == "*":
+			return true
+		case strings.HasPrefix(rule, "."):
+			if strings.HasSuffix(host, rule) || host == rule[1:] {
+				return true
+			}
+		case host == rule || strings.HasSuffix(host, "."+rule):
+			return true
+		}
+	}
+	return false
+}
+
+// NewRoundTripper builds a transport honoring the proxy, trust and client
+// certificate settings. Hosts with a client certificate get their own transport
+// so the certificate is only ever presented to that host.
+func (c *Config) NewRoundTripper() (http.RoundTripper, error) {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+
+	if c.ProxyURL != "" {
+		proxyURL, err := url.Parse(c.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("unsupported proxy scheme %q", proxyURL.Scheme)
+		}
+		base.Proxy = func(req *http.Request) (*url.URL, error) {
+			if c.BypassProxy(req.URL.Hostname()) {
+				return nil, nil
+			}
+			return proxyURL, nil
+		}
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.InsecureSkipVerify}
+	if len(c.CAFiles) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		for _, file := range c.CAFiles {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			if !pool.AppendCertsFromPEM(data) {
+				return nil, fmt.Errorf("no certificates found in %s", file)
+			}
+		}
+		tlsConfig.RootCAs = pool
+	}
+	base.TLSClientConfig = tlsConfig
+
+	if len(c.ClientCerts) == 0 {
+		return base, nil
+	}
+
+	rt := &hostRoundTripper{fallback: base, hosts: make(map[string]http.RoundTripper)}
+	for host, cc := range c.ClientCerts {
+		cert, err := tls.LoadX509KeyPair(cc.CertFile, cc.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate for %s: %w", host, err)
+		}
+		hostTransport := base.Clone()
+		hostTransport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+		rt.hosts[host] = hostTransport
+	}
+	return rt, nil
+}
+
+// CASPKIHashes returns base64 SHA-256 hashes of the extra CA public keys, the
+// format Chromium's --ignore-certificate-errors-spki-list flag expects
+func (c *Config) CASPKIHashes() ([]string, error) {
+	var hashes []string
+	for _, file := range c.CAFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for {
+			var block *pem.Block
+			block, data = pem.Decode(data)
+			if block == nil {
+				break
+			}
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("parsing %s: %w", file, err)
+			}
+			sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+			hashes = append(hashes, base64.StdEncoding.EncodeToString(sum[:]))
+		}
+	}
+	return hashes, nil
+}
+
+type hostRoundTripper struct {
+	fallback http.RoundTripper
+	hosts    map[string]http.RoundTripper
+}
+
+func (rt *hostRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t, ok := rt.hosts[strings.ToLower(req.URL.Hostname())]; ok {
+		return t.RoundTrip(req)
+	}
+	return rt.fallback.RoundTrip(req)
+}
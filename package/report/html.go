@@ -0,0 +1,102 @@
+package report
+
+import (
+	"html/template"
+
+	"github.com/MdSadiqMd/Scrape404/package/types"
+)
+
+// view adds lookups the HTML template needs on top of the report
+type view struct {
+	*Report
+	EvidenceFor map[string]types.PageEvidence
+}
+
+func newView(r *Report) view {
+	v := view{Report: r, EvidenceFor: make(map[string]types.PageEvidence)}
+	for _, e := range r.Evidence {
+		v.EvidenceFor[e.Page] = e
+	}
+	return v
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Dead links for {{.URL}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2rem; }
+th, td { border: 1px solid #ccc; padding: .4rem .6rem; text-align: left; vertical-align: top; word-break: break-all; }
+th { background: #f3f3f3; }
+code { word-break: break-word; color: #555; }
+.status { color: #b00; font-weight: bold; }
+.shot { max-width: 320px; border: 1px solid #ccc; }
+</style>
+</head>
+<body>
+<h1>Dead links for <a href="{{.URL}}">{{.URL}}</a></h1>
+<p>Scan {{.ScanID}} ({{.Mode}}), {{.PagesVisited}} pages visited, {{.LinksChecked}} links checked in {{.Duration}}.</p>
+
+<h2>Dead Links ({{len .DeadLinks}})</h2>
+{{if .DeadLinks}}
+<table>
+<tr><th>Dead Link</th><th>Status</th><th>Type</th><th>Found On</th><th>Element</th><th>Via</th><th>Evidence</th></tr>
+{{range .DeadLinks}}
+<tr>
+<td><a href="{{.URL}}">{{.URL}}</a></td>
+<td class="status">{{if .StatusCode}}{{.StatusCode}}{{else}}ERROR{{end}}</td>
+<td>{{.Type}}</td>
+<td><a href="{{.FoundOn}}">{{.FoundOn}}</a>{{if .Line}}<br>line {{.Line}}{{end}}
+{{if gt .PageCount 1}}<details><summary>{{.PageCount}} pages</summary>{{range .Pages}}<a href="{{.}}">{{.}}</a><br>{{end}}{{if gt .PageCount (len .Pages)}}and {{.PageCount}} in total{{end}}</details>{{end}}</td>
+<td>{{if .Tag}}<code>&lt;{{.Tag}}{{if .Attr}} {{.Attr}}{{end}}&gt;</code>{{end}}{{if .Text}} &ldquo;{{.Text}}&rdquo;{{end}}{{if .Selector}}<br><code>{{.Selector}}</code>{{end}}</td>
+<td>{{.Source}}</td>
+<td>{{with index $.EvidenceFor .FoundOn}}{{if .Screenshot}}<a href="{{.Screenshot}}">screenshot</a> {{end}}{{if .HAR}}<a href="{{.HAR}}">HAR</a>{{end}}{{end}}</td>
+</tr>
+{{end}}
+</table>
+
+<h2>Dead Links by Page ({{len .DeadByPage}} pages)</h2>
+<table>
+<tr><th>Page</th><th>Dead Links</th></tr>
+{{range .DeadByPage}}
+<tr>
+<td><a href="{{.Page}}">{{.Page}}</a></td>
+<td>{{range .DeadLinks}}<a href="{{.}}">{{.}}</a><br>{{end}}</td>
+</tr>
+{{end}}
+</table>
+{{else}}
+<p>No dead links found.</p>
+{{end}}
+
+{{if .JSErrors}}
+<h2>JavaScript Errors ({{len .JSErrors}})</h2>
+<table>
+<tr><th>Kind</th><th>Message</th><th>Location</th><th>Count</th><th>Pages</th></tr>
+{{range .JSErrors}}
+<tr>
+<td>{{.Kind}}</td><td>{{.Message}}</td><td>{{.Location}}</td><td>{{.Count}}</td>
+<td>{{range .Pages}}<a href="{{.}}">{{.}}</a><br>{{end}}</td>
+</tr>
+{{end}}
+</table>
+{{end}}
+
+{{if .Evidence}}
+<h2>Evidence ({{len .Evidence}} pages)</h2>
+<table>
+<tr><th>Page</th><th>Screenshot</th><th>HAR</th></tr>
+{{range .Evidence}}
+<tr>
+<td><a href="{{.Page}}">{{.Page}}</a></td>
+<td>{{if .Screenshot}}<a href="{{.Screenshot}}"><img class="shot" src="{{.Screenshot}}" alt="Screenshot of {{.Page}}"></a>{{end}}</td>
+<td>{{if .HAR}}<a href="{{.HAR}}">{{.HAR}}</a>{{end}}</td>
+</tr>
+{{end}}
+</table>
+{{end}}
+</body>
+</html>
+`))
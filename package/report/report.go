@@ -0,0 +1,97 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/MdSadiqMd/Scrape404/package/types"
+)
+
+const (
+	jsonFile = "report.json"
+	htmlFile = "report.html"
+)
+
+// Report is the final result of a scan, written next to its checkpoint
+type Report struct {
+	ScanID       string           `json:"scanId"`
+	URL          string           `json:"url"`
+	Mode         string           `json:"mode"`
+	GeneratedAt  time.Time        `json:"generatedAt"`
+	Duration     string           `json:"duration"`
+	PagesVisited int              `json:"pagesVisited"`
+	LinksChecked int              `json:"linksChecked"`
+	DeadLinks    []types.DeadLink `json:"deadLinks"`
+	// DeadByPage groups DeadLinks by the pages they appear on
+	DeadByPage []types.PageDeadLinks `json:"deadLinksByPage"`
+	JSErrors   []types.JSError       `json:"jsErrors,omitempty"`
+	Evidence   []types.PageEvidence  `json:"evidence,omitempty"`
+}
+
+func Dir(resultsDir, scanID string) string {
+	return filepath.Join(resultsDir, scanID)
+}
+
+// Write saves report.json, report.sarif and report.html for the scan and returns the HTML path.
+// Evidence paths are rewritten relative to the report so both files link to them.
+func Write(resultsDir string, r *Report) (string, error) {
+	dir := Dir(resultsDir, r.ScanID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	out := *r
+	out.GeneratedAt = time.Now()
+	out.Evidence = make([]types.PageEvidence, 0, len(r.Evidence))
+	for _, e := range r.Evidence {
+		e.Screenshot = relativeTo(dir, resultsDir, e.Screenshot)
+		e.HAR = relativeTo(dir, resultsDir, e.HAR)
+		out.Evidence = append(out.Evidence, e)
+	}
+	if out.DeadLinks == nil {
+		out.DeadLinks = []types.DeadLink{}
+	}
+	if out.DeadByPage == nil {
+		out.DeadByPage = []types.PageDeadLinks{}
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, jsonFile), data, 0o644); err != nil {
+		return "", err
+	}
+
+	sarif, err := json.MarshalIndent(newSARIF(&out), "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, sarifFile), sarif, 0o644); err != nil {
+		return "", err
+	}
+
+	htmlPath := filepath.Join(dir, htmlFile)
+	f, err := os.Create(htmlPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if err := htmlTemplate.Execute(f, newView(&out)); err != nil {
+		return "", err
+	}
+	return htmlPath, nil
+}
+
+func relativeTo(dir, resultsDir, path string) string {
+	if path == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(dir, filepath.Join(resultsDir, path))
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
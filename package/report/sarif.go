@@ -0,0 +1,120 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+const sarifFile = "report.sarif"
+
+// SARIF 2.1.0, just the parts code scanning tools need to place a finding
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                        `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult                    `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+const (
+	sarifRuleID = "dead-link"
+	// sarifSourceRoot is the base of relative paths in sources mode
+	sarifSourceRoot = "SRCROOT"
+)
+
+// newSARIF turns the dead links into SARIF results. Sources mode reports
+// "path:line" as FoundOn, which becomes a path relative to the scanned
+// directory and a line, so editors and code scanning can jump to it.
+func newSARIF(r *Report) sarifLog {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "Scrape404",
+			InformationURI: "https://github.com/MdSadiqMd/Scrape404",
+			Rules: []sarifRule{{
+				ID:               sarifRuleID,
+				ShortDescription: sarifMessage{Text: "Link target is missing or unreachable"},
+			}},
+		}},
+		Results: []sarifResult{},
+	}
+	sources := r.Mode == "sources"
+	if sources {
+		run.OriginalURIBaseIDs = map[string]sarifArtifactLocation{
+			sarifSourceRoot: {URI: "file://" + strings.TrimSuffix(r.URL, "/") + "/"},
+		}
+	}
+
+	for _, link := range r.DeadLinks {
+		status := "error"
+		if link.StatusCode > 0 {
+			status = fmt.Sprintf("status %d", link.StatusCode)
+		}
+		location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: link.FoundOn}}
+		if sources {
+			location.ArtifactLocation.URI = strings.TrimSuffix(link.FoundOn, fmt.Sprintf(":%d", link.Line))
+			location.ArtifactLocation.URIBaseID = sarifSourceRoot
+		}
+		if link.Line > 0 {
+			location.Region = &sarifRegion{StartLine: link.Line}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    sarifRuleID,
+			Level:     "error",
+			Message:   sarifMessage{Text: fmt.Sprintf("Dead %s %s (%s)", link.Type, link.URL, status)},
+			Locations: []sarifLocation{{PhysicalLocation: location}},
+		})
+	}
+	return sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+}
@@ -0,0 +1,25 @@
+package types
+
+const (
+	EngineChromium = "chromium"
+	EngineFirefox  = "firefox"
+	EngineWebKit   = "webkit"
+)
+
+// BrowserConfig selects and sets up the browser used in Playwright mode, zero
+// values keep Playwright's defaults
+type BrowserConfig struct {
+	Engine string `json:"engine,omitempty"`
+
+	// Environment, SkipInstall expects the driver and browsers to be present already
+	SkipInstall    bool   `json:"skipInstall,omitempty"`
+	ExecutablePath string `json:"executablePath,omitempty"`
+	DriverDir      string `json:"driverDir,omitempty"`
+	Headed         bool   `json:"headed,omitempty"`
+
+	// Page emulation
+	ViewportWidth  int    `json:"viewportWidth,omitempty"`
+	ViewportHeight int    `json:"viewportHeight,omitempty"`
+	Locale         string `json:"locale,omitempty"`
+	Timezone       string `json:"timezone,omitempty"`
+}
@@ -0,0 +1,64 @@
+package types
+
+type ScanConfig struct {
+	ScanID        string `json:"scanId"`
+	URL           string `json:"url"`
+	MaxDepth      int    `json:"maxDepth"`
+	DelayMs       int    `json:"delayMs"`
+	Parallelism   int    `json:"parallelism"`
+	TimeoutSec    int    `json:"timeoutSec"`
+	UserAgent     string `json:"userAgent"`
+	UsePlaywright bool   `json:"usePlaywright"`
+	// SourceDir checks the built site in this directory, served at URL, instead of crawling a server
+	SourceDir string `json:"sourceDir,omitempty"`
+	// SourcesDir is a repository whose Markdown and reStructuredText files are checked
+	SourcesDir string `json:"sourcesDir,omitempty"`
+	// FailOnDeadLinks makes the scan return an error when any dead link is found
+	FailOnDeadLinks bool `json:"failOnDeadLinks,omitempty"`
+	// Hybrid crawls over HTTP and renders with Playwright only the pages that look
+	// incomplete without JavaScript or match RenderURLPatterns
+	Hybrid            bool     `json:"hybrid,omitempty"`
+	RenderURLPatterns []string `json:"renderUrlPatterns,omitempty"`
+
+	// Linked documents, CheckPDFs downloads same-site PDFs up to MaxPDFSizeMB and checks their links
+	CheckPDFs    bool `json:"checkPdfs,omitempty"`
+	MaxPDFSizeMB int  `json:"maxPdfSizeMb,omitempty"`
+
+	// JavaScript error thresholds for Playwright scans, negative means unlimited
+	MaxJSErrors     int `json:"maxJsErrors"`
+	MaxJSExceptions int `json:"maxJsExceptions"`
+
+	// Playwright browser engine, installation and emulation settings
+	Browser BrowserConfig `json:"browser"`
+
+	// Playwright rendering, matching subresources are aborted and checked over HTTP
+	// instead, pages count as loaded at WaitUntil and once WaitForSelector matches
+	BlockResourceTypes []string `json:"blockResourceTypes,omitempty"`
+	BlockURLPatterns   []string `json:"blockUrlPatterns,omitempty"`
+	WaitUntil          string   `json:"waitUntil,omitempty"`
+	WaitForSelector    string   `json:"waitForSelector,omitempty"`
+
+	// SPA exploration clicks nav links and buttons to find client-side routes,
+	// except those matching ExploreSkip texts or ExploreSkipSelectors. Pages
+	// matching NotFoundSelector are reported as dead routes.
+	ExploreSPA           bool     `json:"exploreSpa,omitempty"`
+	ExploreSkip          []string `json:"exploreSkip,omitempty"`
+	ExploreSkipSelectors []string `json:"exploreSkipSelectors,omitempty"`
+	NotFoundSelector     string   `json:"notFoundSelector,omitempty"`
+
+	// Evidence for pages with dead links or JavaScript errors, saved under ResultsDir
+	CaptureScreenshots bool `json:"captureScreenshots,omitempty"`
+	CaptureHAR         bool `json:"captureHar,omitempty"`
+
+	// Recreate each pooled Playwright context after this many pages, 0 keeps them for the whole scan
+	ContextResetEvery int `json:"contextResetEvery,omitempty"`
+
+	// Playwright login, LoginConfig is a JSON LoginFlow and StorageState the file
+	// the resulting cookies and localStorage are saved to and reused from
+	LoginConfig  string `json:"loginConfig,omitempty"`
+	StorageState string `json:"storageState,omitempty"`
+
+	// Checkpointing
+	ResultsDir         string `json:"resultsDir"`
+	CheckpointInterval int    `json:"checkpointInterval"`
+}
@@ -1,8 +1,32 @@
 package types
 
 type DeadLink struct {
-	URL        string
-	StatusCode int
-	FoundOn    string
-	Type       string
+	URL        string `json:"url"`
+	StatusCode int    `json:"statusCode"`
+	FoundOn    string `json:"foundOn"`
+	Type       string `json:"type"`
+	// Source is the stylesheet or manifest the URL was found in, empty when it is FoundOn itself
+	Source string `json:"source,omitempty"`
+	// Pages lists the pages the link appears on, capped, with FoundOn first.
+	// PageCount is the total number of pages.
+	Pages     []string `json:"pages,omitempty"`
+	PageCount int      `json:"pageCount,omitempty"`
+	LinkContext
+}
+
+// PageDeadLinks is the page-centric view of the dead links
+type PageDeadLinks struct {
+	Page      string   `json:"page"`
+	DeadLinks []string `json:"deadLinks"`
+}
+
+// LinkContext locates the element a URL came from on its page
+type LinkContext struct {
+	// Text is the anchor text or alt text of the element
+	Text     string `json:"text,omitempty"`
+	Selector string `json:"selector,omitempty"`
+	// Line is the element's line in the page's HTML, 0 when the page was rendered
+	Line int    `json:"line,omitempty"`
+	Tag  string `json:"tag,omitempty"`
+	Attr string `json:"attr,omitempty"`
 }
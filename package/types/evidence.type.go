@@ -0,0 +1,9 @@
+package types
+
+// PageEvidence points to the files captured for a page with dead links or
+// JavaScript errors, paths are relative to the results directory
+type PageEvidence struct {
+	Page       string `json:"page"`
+	Screenshot string `json:"screenshot,omitempty"`
+	HAR        string `json:"har,omitempty"`
+}
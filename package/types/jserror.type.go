@@ -0,0 +1,16 @@
+package types
+
+const (
+	JSErrorConsole            = "console"
+	JSErrorException          = "exception"
+	JSErrorUnhandledRejection = "unhandled-rejection"
+)
+
+// JSError groups identical JavaScript errors by kind, message and source location
+type JSError struct {
+	Kind     string   `json:"kind"`
+	Message  string   `json:"message"`
+	Location string   `json:"location,omitempty"`
+	Count    int      `json:"count"`
+	Pages    []string `json:"pages"`
+}
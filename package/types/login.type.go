@@ -0,0 +1,15 @@
+package types
+
+// LoginStep is one action of a scripted login, Action is fill, click, press or wait
+type LoginStep struct {
+	Action    string `json:"action"`
+	Selector  string `json:"selector,omitempty"`
+	Value     string `json:"value,omitempty"`
+	URL       string `json:"url,omitempty"`
+	TimeoutMs int    `json:"timeoutMs,omitempty"`
+}
+
+type LoginFlow struct {
+	URL   string      `json:"url"`
+	Steps []LoginStep `json:"steps"`
+}
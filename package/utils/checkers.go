@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Checker checks the links of one URL scheme. It returns an HTTP-like status
+// code, or why the link could not be checked.
+type Checker interface {
+	Check(link string) (statusCode int, errMsg string)
+}
+
+// CheckerFunc adapts a function to a Checker
+type CheckerFunc func(link string) (int, string)
+
+func (f CheckerFunc) Check(link string) (int, string) {
+	return f(link)
+}
+
+// CheckerOptions says how CheckLink treats the results of a Checker
+type CheckerOptions struct {
+	// LinkType replaces the type of the element the link was found in, such as
+	// "docs" for docs:// links, when it is set
+	LinkType string
+	// Cached results go through the link cache. Leave it off for cheap local
+	// checks whose answer changes with every build.
+	Cached bool
+}
+
+type schemeChecker struct {
+	Checker
+	CheckerOptions
+}
+
+var (
+	checkersMu sync.RWMutex
+	checkers   = map[string]schemeChecker{
+		"http":   {CheckerFunc(fetchLinkStatus), CheckerOptions{Cached: true}},
+		"https":  {CheckerFunc(fetchLinkStatus), CheckerOptions{Cached: true}},
+		"ftp":    {CheckerFunc(checkFTP), CheckerOptions{Cached: true}},
+		"data":   {CheckerFunc(checkDataURI), CheckerOptions{}},
+		"file":   {CheckerFunc(checkFile), CheckerOptions{}},
+		"mailto": {contactChecker(validateMailto), CheckerOptions{LinkType: MailtoType}},
+		"tel":    {contactChecker(validateTel), CheckerOptions{LinkType: TelType}},
+	}
+)
+
+// RegisterChecker makes CheckLink use c for links of scheme, replacing the
+// built-in checker if there is one. Embedders register their own schemes,
+// such as docs://, before scanning. Links under a local site are still
+// resolved on disk, and http and https overrides always use the link cache.
+func RegisterChecker(scheme string, c Checker, opts CheckerOptions) {
+	scheme = strings.ToLower(scheme)
+	if scheme == "http" || scheme == "https" {
+		opts.Cached = true
+	}
+	checkersMu.Lock()
+	defer checkersMu.Unlock()
+	checkers[scheme] = schemeChecker{c, opts}
+}
+
+func lookupChecker(link string) (schemeChecker, string, bool) {
+	scheme := linkScheme(link)
+	checkersMu.RLock()
+	defer checkersMu.RUnlock()
+	c, ok := checkers[scheme]
+	return c, scheme, ok
+}
+
+func linkScheme(link string) string {
+	if i := strings.IndexByte(link, ':'); i > 0 {
+		return strings.ToLower(link[:i])
+	}
+	return ""
+}
+
+// IsWebLink reports http and https links, the only ones that can be crawled
+func IsWebLink(link string) bool {
+	scheme := linkScheme(link)
+	return scheme == "http" || scheme == "https"
+}
+
+// displayLink shortens data: URIs, which can be kilobytes long, for printing
+func displayLink(link string) string {
+	if linkScheme(link) == "data" {
+		return truncateString(link, 60)
+	}
+	return link
+}
+
+func contactChecker(validate func(string) string) Checker {
+	return CheckerFunc(func(link string) (int, string) {
+		return 0, validate(link)
+	})
+}
+
+// checkFile checks a file:// link on disk, answering like an HTTP server would
+func checkFile(link string) (int, string) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return 0, fmt.Sprintf("Request Error: %s", err)
+	}
+	if _, err := os.Stat(filepath.FromSlash(u.Path)); err != nil {
+		return http.StatusNotFound, ""
+	}
+	return http.StatusOK, ""
+}
+
+// checkDataURI validates the media type and encoding of a data: URI
+func checkDataURI(link string) (int, string) {
+	header, payload, ok := strings.Cut(link[len("data:"):], ",")
+	if !ok {
+		return 0, "Invalid Data URI: missing comma"
+	}
+
+	mediaType := header
+	base64Encoded := false
+	if strings.HasSuffix(strings.ToLower(header), ";base64") {
+		mediaType = header[:len(header)-len(";base64")]
+		base64Encoded = true
+	}
+	// Parameters such as ;charset=utf-8 or the common ;utf8 are ignored by
+	// browsers when malformed, so only the type itself has to be valid
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+	if mediaType != "" {
+		if unescaped, err := url.PathUnescape(mediaType); err == nil {
+			mediaType = unescaped
+		}
+		mt, _, err := mime.ParseMediaType(mediaType)
+		if err != nil || !strings.Contains(mt, "/") {
+			return 0, fmt.Sprintf("Invalid Data URI: bad media type %q", mediaType)
+		}
+	}
+
+	// Plain payloads are taken as they are, browsers tolerate stray % signs
+	if base64Encoded {
+		data := payload
+		if unescaped, err := url.PathUnescape(payload); err == nil {
+			data = unescaped
+		}
+		data = strings.Join(strings.Fields(data), "")
+		if _, err := base64.StdEncoding.DecodeString(data); err != nil {
+			// Unpadded payloads are common and accepted by browsers
+			if _, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(data, "=")); err != nil {
+				return 0, fmt.Sprintf("Invalid Data URI: bad base64 data: %s", err)
+			}
+		}
+	}
+	return http.StatusOK, ""
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCheckDataURI(t *testing.T) {
+	tests := []struct {
+		name  string
+		link  string
+		valid bool
+	}{
+		{"base64 PNG", "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==", true},
+		{"unpadded base64", "data:text/plain;base64,aGk", true},
+		{"percent-encoded text", "data:text/plain,hello%20world", true},
+		{"no media type", "data:,plain", true},
+		{"charset only", "data:;charset=utf-8,x", true},
+		{"SVG with utf8 parameter", `data:image/svg+xml;utf8,<svg xmlns="http://www.w3.org/2000/svg"></svg>`, true},
+		{"SVG with charset", "data:image/svg+xml;charset=utf-8,%3Csvg%3E%3C/svg%3E", true},
+		{"raw percent sign", "data:text/html,<p>100% sure</p>", true},
+		{"base64 with whitespace", "data:text/plain;base64,aGVs bG8=", true},
+		{"missing comma", "data:nocomma", false},
+		{"bad media type", "data:bogus;base64,AAAA", false},
+		{"bad base64", "data:image/png;base64,@@@notbase64", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, errMsg := checkDataURI(tt.link)
+			if valid := errMsg == "" && status == http.StatusOK; valid != tt.valid {
+				t.Errorf("checkDataURI(%q) = %d %q, want valid %v", tt.link, status, errMsg, tt.valid)
+			}
+		})
+	}
+}
+
+func TestRegisterChecker(t *testing.T) {
+	defer func(saved map[string]schemeChecker) { checkers = saved }(copyCheckers())
+
+	RegisterChecker("DOCS", CheckerFunc(func(link string) (int, string) {
+		if strings.HasSuffix(link, "/missing") {
+			return http.StatusNotFound, ""
+		}
+		return http.StatusOK, ""
+	}), CheckerOptions{LinkType: "docs"})
+	RegisterChecker("https", CheckerFunc(fetchLinkStatus), CheckerOptions{})
+
+	c, scheme, ok := lookupChecker("docs://guide/missing")
+	if !ok || scheme != "docs" || c.LinkType != "docs" || c.Cached {
+		t.Fatalf("lookupChecker(docs://) = %+v %q %v", c.CheckerOptions, scheme, ok)
+	}
+	if status, _ := c.Check("docs://guide/missing"); status != http.StatusNotFound {
+		t.Errorf("docs checker status = %d, want 404", status)
+	}
+	if c, _, _ := lookupChecker("https://example.com"); !c.Cached {
+		t.Error("https override lost the link cache")
+	}
+	if _, _, ok := lookupChecker("htps://typo.example"); ok {
+		t.Error("unknown scheme has a checker")
+	}
+}
+
+func copyCheckers() map[string]schemeChecker {
+	checkersMu.RLock()
+	defer checkersMu.RUnlock()
+	saved := make(map[string]schemeChecker, len(checkers))
+	for scheme, c := range checkers {
+		saved[scheme] = c
+	}
+	return saved
+}
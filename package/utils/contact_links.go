@@ -0,0 +1,189 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Link types of mailto: and tel: links, reported apart from HTTP links
+const (
+	MailtoType = "mailto"
+	TelType    = "tel"
+)
+
+const dnsTimeout = 5 * time.Second
+
+var (
+	hostLabel = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]*[A-Za-z0-9])?$`)
+	e164      = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+	// RFC 3966 local numbers, such as extensions and short codes, have no +
+	localTel = regexp.MustCompile(`^[0-9*#]{2,15}$`)
+	// RFC 3966 visual separators, which carry no meaning
+	telSeparators = strings.NewReplacer("-", "", ".", "", "(", "", ")", "", " ", "")
+)
+
+var (
+	checkMailDomains bool
+	strictContacts   bool
+	mailResolver     = net.DefaultResolver
+	mailDomainsMu    sync.Mutex
+	mailDomains      = make(map[string]string)
+)
+
+// SetContactChecks enables MX/A lookups for mailto: domains. strict rejects
+// local tel: numbers and mail domains without a top-level domain, which are
+// common on intranets. dnsServer is a host:port to query instead of the system
+// resolver, such as a local stand-in.
+func SetContactChecks(checkDomains, strict bool, dnsServer string) error {
+	checkMailDomains = checkDomains
+	strictContacts = strict
+	if dnsServer == "" {
+		mailResolver = net.DefaultResolver
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(dnsServer); err != nil {
+		return fmt.Errorf("invalid DNS server %q: %w", dnsServer, err)
+	}
+	mailResolver = &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, dnsServer)
+		},
+	}
+	return nil
+}
+
+// validateMailto returns why a mailto: link is invalid, empty when it is fine
+func validateMailto(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return fmt.Sprintf("Invalid Address: %s", err)
+	}
+	to, err := url.PathUnescape(u.Opaque)
+	if err != nil {
+		return fmt.Sprintf("Invalid Address: %s", err)
+	}
+
+	var addresses []string
+	for _, list := range append([]string{to}, u.Query()["to"]...) {
+		for _, addr := range strings.Split(list, ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				addresses = append(addresses, addr)
+			}
+		}
+	}
+	if len(addresses) == 0 {
+		return "Invalid Address: no recipient"
+	}
+
+	for _, addr := range addresses {
+		domain, errMsg := mailDomain(addr)
+		if errMsg == "" && checkMailDomains {
+			errMsg = lookupMailDomain(domain)
+		}
+		if errMsg != "" {
+			return errMsg
+		}
+	}
+	return ""
+}
+
+// mailDomain checks the syntax of a bare address and returns its domain
+func mailDomain(addr string) (string, string) {
+	parsed, err := mail.ParseAddress(addr)
+	// Display names like "Name <a@b>" are not allowed in mailto: links
+	if err != nil || parsed.Address != addr {
+		return "", fmt.Sprintf("Invalid Address: %s", addr)
+	}
+	domain := addr[strings.LastIndex(addr, "@")+1:]
+	if strings.HasPrefix(domain, "[") && strings.HasSuffix(domain, "]") {
+		return "", ""
+	}
+	labels := strings.Split(strings.TrimSuffix(domain, "."), ".")
+	if len(labels) < 2 && strictContacts {
+		return "", fmt.Sprintf("Invalid Address: %s has no top-level domain", addr)
+	}
+	for _, label := range labels {
+		if len(label) > 63 || !hostLabel.MatchString(label) {
+			return "", fmt.Sprintf("Invalid Address: %s has an invalid domain", addr)
+		}
+	}
+	return strings.ToLower(domain), ""
+}
+
+// lookupMailDomain checks that domain can receive mail, remembering the answer
+// since the same few domains are linked from most pages
+func lookupMailDomain(domain string) string {
+	if domain == "" {
+		return ""
+	}
+	mailDomainsMu.Lock()
+	errMsg, ok := mailDomains[domain]
+	mailDomainsMu.Unlock()
+	if ok {
+		return errMsg
+	}
+
+	errMsg = resolveMailDomain(domain)
+	mailDomainsMu.Lock()
+	mailDomains[domain] = errMsg
+	mailDomainsMu.Unlock()
+	return errMsg
+}
+
+func resolveMailDomain(domain string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), dnsTimeout)
+	defer cancel()
+
+	mxs, err := mailResolver.LookupMX(ctx, domain)
+	if err == nil && len(mxs) > 0 {
+		// A single "." MX is RFC 7505's null MX, the domain accepts no mail
+		if len(mxs) == 1 && mxs[0].Host == "." {
+			return fmt.Sprintf("Mail Domain Error: %s accepts no mail", domain)
+		}
+		return ""
+	}
+	if err != nil && !isNotFound(err) {
+		return fmt.Sprintf("DNS Error: %s", err)
+	}
+
+	// Without MX records mail goes to the domain's address records
+	if _, err := mailResolver.LookupHost(ctx, domain); err != nil {
+		if isNotFound(err) {
+			return fmt.Sprintf("Mail Domain Error: %s has no MX or A records", domain)
+		}
+		return fmt.Sprintf("DNS Error: %s", err)
+	}
+	return ""
+}
+
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+// validateTel returns why a tel: link is invalid, empty when it is fine
+func validateTel(link string) string {
+	number := link[len("tel:"):]
+	if unescaped, err := url.PathUnescape(number); err == nil {
+		number = unescaped
+	}
+	// Parameters such as ;ext=123 follow the number
+	if i := strings.IndexByte(number, ';'); i >= 0 {
+		number = number[:i]
+	}
+	digits := telSeparators.Replace(number)
+	if !e164.MatchString(digits) && (strictContacts || !localTel.MatchString(digits)) {
+		return fmt.Sprintf("Invalid Phone Number: %s is not in E.164 format", number)
+	}
+	return ""
+}
@@ -0,0 +1,65 @@
+package utils
+
+import "testing"
+
+func TestValidateMailto(t *testing.T) {
+	tests := []struct {
+		name   string
+		link   string
+		valid  bool
+		strict bool
+	}{
+		{"plain address", "mailto:hi@example.com", true, true},
+		{"query parameters", "mailto:hi@example.com?subject=Hello%20there&cc=b@example.com", true, true},
+		{"several recipients", "mailto:a@example.com,b@example.org", true, true},
+		{"recipient in to parameter", "mailto:?to=a@example.com", true, true},
+		{"percent-encoded address", "mailto:first%2Elast@example.com", true, true},
+		{"IP literal domain", "mailto:root@[192.0.2.1]", true, true},
+		{"no recipient", "mailto:", false, false},
+		{"missing at sign", "mailto:hi.example.com", false, false},
+		{"display name", "mailto:Hi%20%3Chi@example.com%3E", false, false},
+		{"invalid domain label", "mailto:hi@exa_mple.com", false, false},
+		{"one bad recipient", "mailto:a@example.com,broken", false, false},
+		{"no top-level domain", "mailto:admin@localhost", true, false},
+		{"no top-level domain, strict", "mailto:admin@localhost", false, true},
+	}
+	defer func() { strictContacts = false }()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strictContacts = tt.strict
+			if errMsg := validateMailto(tt.link); (errMsg == "") != tt.valid {
+				t.Errorf("validateMailto(%q) = %q, want valid %v", tt.link, errMsg, tt.valid)
+			}
+		})
+	}
+}
+
+func TestValidateTel(t *testing.T) {
+	tests := []struct {
+		name   string
+		link   string
+		valid  bool
+		strict bool
+	}{
+		{"E.164", "tel:+14155552671", true, true},
+		{"visual separators", "tel:+1-415-555-2671", true, true},
+		{"percent-encoded spaces", "tel:+44%2020%207946%200958", true, true},
+		{"extension parameter", "tel:+14155552671;ext=123", true, true},
+		{"letters", "tel:+1-800-FLOWERS", false, false},
+		{"leading zero country code", "tel:+0123456", false, false},
+		{"too long", "tel:+1234567890123456", false, false},
+		{"empty", "tel:", false, false},
+		{"local number", "tel:5551234", true, false},
+		{"local number, strict", "tel:5551234", false, true},
+		{"short code", "tel:*99#", true, false},
+	}
+	defer func() { strictContacts = false }()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strictContacts = tt.strict
+			if errMsg := validateTel(tt.link); (errMsg == "") != tt.valid {
+				t.Errorf("validateTel(%q) = %q, want valid %v", tt.link, errMsg, tt.valid)
+			}
+		})
+	}
+}
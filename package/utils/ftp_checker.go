@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+const ftpTimeout = 10 * time.Second
+
+// checkFTP logs in to the server of an ftp:// link and checks that its file
+// or directory exists, without downloading anything
+func checkFTP(link string) (int, string) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return 0, fmt.Sprintf("Request Error: %s", err)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "21")
+	}
+
+	user, pass := "anonymous", "anonymous@"
+	if u.User != nil {
+		user = u.User.Username()
+		pass, _ = u.User.Password()
+	}
+	// Commands end at CR LF, a decoded %0D%0A would smuggle in further commands
+	for _, field := range []string{u.Path, user, pass} {
+		if strings.ContainsAny(field, "\r\n\x00") {
+			return 0, "Invalid URL: control characters in FTP path or credentials"
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ftpTimeout)
+	defer cancel()
+	conn, err := Network().DialContext(ctx, host)
+	if err != nil {
+		return 0, fmt.Sprintf("Network Error: %s", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(ftpTimeout))
+	c := textproto.NewConn(conn)
+	defer c.Cmd("QUIT")
+
+	if _, _, err := c.ReadResponse(220); err != nil {
+		return 0, fmt.Sprintf("FTP Error: %s", err)
+	}
+
+	code, msg, err := ftpCmd(c, "USER %s", user)
+	if err == nil && code == 331 {
+		code, msg, err = ftpCmd(c, "PASS %s", pass)
+	}
+	if err != nil {
+		return 0, fmt.Sprintf("Network Error: %s", err)
+	}
+	if code != 230 && code != 202 {
+		return 0, fmt.Sprintf("FTP Error: login failed: %d %s", code, msg)
+	}
+
+	file := path.Clean("/" + u.Path)
+	if !strings.HasSuffix(u.Path, "/") {
+		ftpCmd(c, "TYPE I")
+		code, _, err := ftpCmd(c, "SIZE %s", file)
+		if err != nil {
+			return 0, fmt.Sprintf("Network Error: %s", err)
+		}
+		if code == 213 {
+			return http.StatusOK, ""
+		}
+	}
+
+	// Directories have no size, and some servers don't implement SIZE
+	code, _, err = ftpCmd(c, "CWD %s", file)
+	if err != nil {
+		return 0, fmt.Sprintf("Network Error: %s", err)
+	}
+	if code == 250 {
+		return http.StatusOK, ""
+	}
+	return http.StatusNotFound, ""
+}
+
+// ftpCmd sends a command and returns its reply, errors are only returned for
+// broken connections so callers can act on the reply code
+func ftpCmd(c *textproto.Conn, format string, args ...any) (int, string, error) {
+	if _, err := c.Cmd(format, args...); err != nil {
+		return 0, "", err
+	}
+	code, msg, err := c.ReadResponse(0)
+	if _, ok := err.(*textproto.Error); ok {
+		err = nil
+	}
+	return code, msg, err
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// serveFTP answers the commands checkFTP sends for a server holding /pub/file.txt,
+// recording every command it receives
+func serveFTP(t *testing.T) (string, func() []string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	var mu sync.Mutex
+	var received []string
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				fmt.Fprint(conn, "220 ready\r\n")
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					line := scanner.Text()
+					mu.Lock()
+					received = append(received, line)
+					mu.Unlock()
+					cmd, arg, _ := strings.Cut(line, " ")
+					switch cmd {
+					case "USER":
+						fmt.Fprint(conn, "331 password please\r\n")
+					case "PASS":
+						fmt.Fprint(conn, "230 logged in\r\n")
+					case "SIZE":
+						if arg == "/pub/file.txt" {
+							fmt.Fprint(conn, "213 5\r\n")
+						} else {
+							fmt.Fprint(conn, "550 no such file\r\n")
+						}
+					case "CWD":
+						if arg == "/pub" {
+							fmt.Fprint(conn, "250 ok\r\n")
+						} else {
+							fmt.Fprint(conn, "550 no such directory\r\n")
+						}
+					case "QUIT":
+						fmt.Fprint(conn, "221 bye\r\n")
+						return
+					default:
+						fmt.Fprint(conn, "200 ok\r\n")
+					}
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String(), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), received...)
+	}
+}
+
+func TestCheckFTP(t *testing.T) {
+	addr, received := serveFTP(t)
+	tests := []struct {
+		name   string
+		link   string
+		status int
+	}{
+		{"file", "ftp://" + addr + "/pub/file.txt", http.StatusOK},
+		{"directory", "ftp://" + addr + "/pub/", http.StatusOK},
+		{"missing file", "ftp://" + addr + "/pub/missing.txt", http.StatusNotFound},
+		{"command in path", "ftp://" + addr + "/x%0D%0ADELE%20y", 0},
+		{"command in password", "ftp://user:pw%0ADELE%20y@" + addr + "/pub/", 0},
+		{"NUL in path", "ftp://" + addr + "/pub%00/", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status, errMsg := checkFTP(tt.link); status != tt.status {
+				t.Errorf("checkFTP(%q) = %d %q, want %d", tt.link, status, errMsg, tt.status)
+			}
+		})
+	}
+	for _, cmd := range received() {
+		if strings.HasPrefix(cmd, "DELE") {
+			t.Errorf("server received injected command %q", cmd)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+type LinkCacheEntry struct {
+	StatusCode int       `json:"statusCode"`
+	Error      string    `json:"error,omitempty"`
+	CheckedAt  time.Time `json:"checkedAt"`
+}
+
+// LinkCache persists link check results across pages, runs and sites
+type LinkCache struct {
+	mu       sync.Mutex
+	path     string
+	entries  map[string]LinkCacheEntry
+	validTTL time.Duration
+	deadTTL  time.Duration
+	errorTTL time.Duration
+	hits     int
+	misses   int
+}
+
+var (
+	linkCache *LinkCache
+	// siteHost is the host of the scanned site, its pages are always checked afresh
+	siteHost string
+)
+
+// SetLinkCache makes CheckLink consult c before making requests, nil disables
+// caching. Links to siteURL's host bypass the cache, a page removed since the
+// last run must not pass as valid.
+func SetLinkCache(c *LinkCache, siteURL string) {
+	linkCache = c
+	siteHost = ""
+	if u, err := url.Parse(siteURL); err == nil {
+		siteHost = strings.ToLower(u.Hostname())
+	}
+}
+
+// cacheFor returns the cache to use for link, nil when it must be checked afresh
+func cacheFor(link string) *LinkCache {
+	if linkCache == nil || siteHost == "" {
+		return linkCache
+	}
+	if u, err := url.Parse(link); err == nil && strings.ToLower(u.Hostname()) == siteHost {
+		return nil
+	}
+	return linkCache
+}
+
+func NewLinkCache(path string, validTTL, deadTTL, errorTTL time.Duration) (*LinkCache, error) {
+	c := &LinkCache{
+		path:     path,
+		entries:  make(map[string]LinkCacheEntry),
+		validTTL: validTTL,
+		deadTTL:  deadTTL,
+		errorTTL: errorTTL,
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *LinkCache) Get(link string) (LinkCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[cacheKey(link)]
+	if ok && time.Since(entry.CheckedAt) > c.ttl(entry) {
+		ok = false
+	}
+	if ok {
+		c.hits++
+	} else {
+		c.misses++
+	}
+	return entry, ok
+}
+
+func (c *LinkCache) Put(link string, statusCode int, errMsg string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[cacheKey(link)] = LinkCacheEntry{
+		StatusCode: statusCode,
+		Error:      errMsg,
+		CheckedAt:  time.Now(),
+	}
+}
+
+func (c *LinkCache) Stats() (hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// Save writes the cache to disk, dropping entries that have already expired
+func (c *LinkCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, entry := range c.entries {
+		if time.Since(entry.CheckedAt) > c.ttl(entry) {
+			delete(c.entries, key)
+		}
+	}
+
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
+
+func (c *LinkCache) ttl(entry LinkCacheEntry) time.Duration {
+	switch {
+	case entry.StatusCode == 0:
+		return c.errorTTL
+	case entry.StatusCode >= 400:
+		return c.deadTTL
+	default:
+		return c.validTTL
+	}
+}
+
+// cacheKey is the normalized link, followed by the fingerprint of the
+// credentials sent with it so results from other credentials aren't reused
+func cacheKey(link string) string {
+	key := NormalizeURL(link)
+	if u, err := url.Parse(link); err == nil {
+		if fp := credentials.Fingerprint(u); fp != "" {
+			key += " auth:" + fp
+		}
+	}
+	return key
+}
+
+// NormalizeURL lowercases scheme and host, drops default ports and fragments
+// so equivalent spellings of a URL share one cache entry
+func NormalizeURL(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	if port != "" {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	u.Host = host
+	u.Fragment = ""
+	if u.Path == "" && u.Host != "" {
+		u.Path = "/"
+	}
+	return u.String()
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/MdSadiqMd/Scrape404/package/auth"
+)
+
+func TestCacheKeyCredentials(t *testing.T) {
+	defer func(saved *auth.Credentials) { credentials = saved }(credentials)
+
+	withToken := func(token string) string {
+		creds := auth.New()
+		if err := creds.AddBearerToken("intranet.example.com=" + token); err != nil {
+			t.Fatal(err)
+		}
+		credentials = creds
+		return cacheKey("https://intranet.example.com/page")
+	}
+	first, second := withToken("one"), withToken("two")
+	if first == second {
+		t.Errorf("links checked with different tokens share the key %q", first)
+	}
+	if first != withToken("one") {
+		t.Errorf("the same token gives different keys")
+	}
+
+	credentials = nil
+	if plain := cacheKey("https://intranet.example.com/page"); plain != "https://intranet.example.com/page" || plain == first {
+		t.Errorf("cacheKey() without credentials = %q", plain)
+	}
+	credentials = auth.New()
+	credentials.AddBearerToken("intranet.example.com=one")
+	if other := cacheKey("https://example.com/"); other != "https://example.com/" {
+		t.Errorf("cacheKey() for a host without credentials = %q", other)
+	}
+}
@@ -1,53 +1,191 @@
 package utils
 
 import (
+	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
+	"github.com/MdSadiqMd/Scrape404/package/auth"
+	"github.com/MdSadiqMd/Scrape404/package/network"
 	"github.com/MdSadiqMd/Scrape404/package/types"
 	"github.com/fatih/color"
 )
 
+var (
+	credentials   *auth.Credentials
+	credentialJar http.CookieJar
+)
+
+// SetCredentials scopes per-host auth headers and imported cookies to link checks
+func SetCredentials(c *auth.Credentials) error {
+	jar, err := c.CookieJar()
+	if err != nil {
+		return err
+	}
+	credentials, credentialJar = c, jar
+	return nil
+}
+
+// Credentials returns the credentials configured with SetCredentials, nil if there are none
+func Credentials() *auth.Credentials {
+	return credentials
+}
+
+var (
+	networkConfig *network.Config
+	linkTransport http.RoundTripper
+)
+
+// SetNetwork applies proxy, trust and client certificate settings to link checks
+func SetNetwork(c *network.Config) error {
+	rt, err := c.NewRoundTripper()
+	if err != nil {
+		return err
+	}
+	networkConfig, linkTransport = c, rt
+	return nil
+}
+
+// Network returns the configuration passed to SetNetwork, nil if there is none
+func Network() *network.Config {
+	return networkConfig
+}
+
+// Transport returns the round tripper built by SetNetwork, nil if there is none
+func Transport() http.RoundTripper {
+	return linkTransport
+}
+
 func CheckLink(link, currentPage, linkType string, deadLinks *[]types.DeadLink, infoColor, successColor, errorColor *color.Color) {
+	CheckLinkIn(link, currentPage, "", linkType, deadLinks, infoColor, successColor, errorColor)
+}
+
+// CheckLinkIn checks a link found inside a stylesheet or other document that
+// currentPage includes, with the Checker registered for its scheme
+func CheckLinkIn(link, currentPage, source, linkType string, deadLinks *[]types.DeadLink, infoColor, successColor, errorColor *color.Color) {
+	infoColor.Printf("  Found %s: %s\n", linkType, displayLink(link))
+
+	dead := types.DeadLink{
+		URL:     link,
+		FoundOn: currentPage,
+		Type:    linkType,
+		Source:  source,
+	}
+	// Files of a local site change with every build, so they bypass the cache
+	if localSite != nil {
+		if _, local, exists := localSite.Resolve(link); local {
+			statusCode := http.StatusOK
+			if !exists {
+				statusCode = http.StatusNotFound
+			}
+			reportLink(dead, statusCode, "", " [local]", deadLinks, successColor, errorColor)
+			return
+		}
+	}
+
+	checker, scheme, ok := lookupChecker(link)
+	if !ok {
+		reportLink(dead, 0, fmt.Sprintf("Unsupported Scheme: %q", scheme), "", deadLinks, successColor, errorColor)
+		return
+	}
+	if checker.LinkType != "" {
+		dead.Type = checker.LinkType
+	}
+	cache := cacheFor(link)
+	if !checker.Cached {
+		cache = nil
+	}
+	if cache != nil {
+		if entry, ok := cache.Get(link); ok {
+			reportLink(dead, entry.StatusCode, entry.Error, " [cached]", deadLinks, successColor, errorColor)
+			return
+		}
+	}
+
+	statusCode, errMsg := checker.Check(link)
+	if cache != nil {
+		cache.Put(link, statusCode, errMsg)
+	}
+	reportLink(dead, statusCode, errMsg, "", deadLinks, successColor, errorColor)
+}
+
+// RecordLink reports a link whose outcome is already known, such as a request
+// the browser made while rendering a page, without requesting it again
+func RecordLink(link, currentPage, linkType string, statusCode int, errMsg string, deadLinks *[]types.DeadLink, infoColor, successColor, errorColor *color.Color) {
 	infoColor.Printf("  Found %s: %s\n", linkType, link)
+	if cache := cacheFor(link); cache != nil {
+		cache.Put(link, statusCode, errMsg)
+	}
+	reportLink(types.DeadLink{URL: link, FoundOn: currentPage, Type: linkType}, statusCode, errMsg, "", deadLinks, successColor, errorColor)
+}
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+// newLinkClient returns a client honoring the configured network settings and credentials
+func newLinkClient() *http.Client {
+	return &http.Client{
+		Timeout:   10 * time.Second,
+		Jar:       credentialJar,
+		Transport: linkTransport,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			if len(via) >= 10 {
 				return http.ErrUseLastResponse
 			}
+			// Re-scope credentials in case the redirect left the original host
+			credentials.Apply(req)
 			return nil
 		},
 	}
+}
+
+// FetchBody downloads at most maxBytes of link, for documents whose contents are checked too
+func FetchBody(link string, maxBytes int64) ([]byte, string, error) {
+	if localSite != nil {
+		if file, local, exists := localSite.Resolve(link); local {
+			if !exists {
+				return nil, "", fmt.Errorf("status %d", http.StatusNotFound)
+			}
+			return readLocal(file, maxBytes)
+		}
+	}
+
+	req, err := http.NewRequest("GET", link, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
+	credentials.Apply(req)
+
+	resp, err := newLinkClient().Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, "", fmt.Errorf("status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes))
+	return body, resp.Header.Get("Content-Type"), err
+}
+
+// fetchLinkStatus returns the final status code of link, or a description of why it could not be fetched
+func fetchLinkStatus(link string) (int, string) {
+	client := newLinkClient()
 
 	// Use HEAD request first (faster), fall back to GET if needed
 	req, err := http.NewRequest("HEAD", link, nil)
 	if err != nil {
-		*deadLinks = append(*deadLinks, types.DeadLink{
-			URL:        link,
-			StatusCode: 0,
-			FoundOn:    currentPage,
-			Type:       linkType,
-		})
-		errorColor.Printf("❌ Dead %s found: %s (Request Error: %s)\n", linkType, link, err)
-		return
+		return 0, fmt.Sprintf("Request Error: %s", err)
 	}
 
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
+	credentials.Apply(req)
 
 	resp, err := client.Do(req)
 	if err != nil {
-		*deadLinks = append(*deadLinks, types.DeadLink{
-			URL:        link,
-			StatusCode: 0,
-			FoundOn:    currentPage,
-			Type:       linkType,
-		})
-		errorColor.Printf("❌ Dead %s found: %s (Network Error: %s)\n", linkType, link, err)
-		return
+		return 0, fmt.Sprintf("Network Error: %s", err)
 	}
 	defer resp.Body.Close()
 
@@ -55,41 +193,33 @@ func CheckLink(link, currentPage, linkType string, deadLinks *[]types.DeadLink,
 	if resp.StatusCode == http.StatusMethodNotAllowed {
 		req, err = http.NewRequest("GET", link, nil)
 		if err != nil {
-			*deadLinks = append(*deadLinks, types.DeadLink{
-				URL:        link,
-				StatusCode: 0,
-				FoundOn:    currentPage,
-				Type:       linkType,
-			})
-			errorColor.Printf("❌ Dead %s found: %s (Request Error: %s)\n", linkType, link, err)
-			return
+			return 0, fmt.Sprintf("Request Error: %s", err)
 		}
 
 		req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
+		credentials.Apply(req)
 		resp, err = client.Do(req)
 		if err != nil {
-			*deadLinks = append(*deadLinks, types.DeadLink{
-				URL:        link,
-				StatusCode: 0,
-				FoundOn:    currentPage,
-				Type:       linkType,
-			})
-			errorColor.Printf("❌ Dead %s found: %s (Network Error: %s)\n", linkType, link, err)
-			return
+			return 0, fmt.Sprintf("Network Error: %s", err)
 		}
 		defer resp.Body.Close()
 	}
 
-	if resp.StatusCode >= 400 {
-		*deadLinks = append(*deadLinks, types.DeadLink{
-			URL:        link,
-			StatusCode: resp.StatusCode,
-			FoundOn:    currentPage,
-			Type:       linkType,
-		})
-		errorColor.Printf("❌ Dead %s found: %s (Status: %d)\n", linkType, link, resp.StatusCode)
+	return resp.StatusCode, ""
+}
+
+func reportLink(dead types.DeadLink, statusCode int, errMsg, suffix string, deadLinks *[]types.DeadLink, successColor, errorColor *color.Color) {
+	if errMsg == "" && statusCode < 400 {
+		successColor.Printf("✓ Valid %s: %s%s\n", dead.Type, displayLink(dead.URL), suffix)
+		return
+	}
+
+	dead.StatusCode = statusCode
+	*deadLinks = append(*deadLinks, dead)
+	if errMsg != "" {
+		errorColor.Printf("❌ Dead %s found: %s (%s)%s\n", dead.Type, displayLink(dead.URL), errMsg, suffix)
 	} else {
-		successColor.Printf("✓ Valid %s: %s\n", linkType, link)
+		errorColor.Printf("❌ Dead %s found: %s (Status: %d)%s\n", dead.Type, displayLink(dead.URL), statusCode, suffix)
 	}
 }
 
@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// LocalSite maps URLs under Base to the files of a built static site in Dir,
+// so same-site links are checked on disk instead of over the network
+type LocalSite struct {
+	Base *url.URL
+	Dir  string
+}
+
+var localSite *LocalSite
+
+// SetLocalSite makes link checks resolve URLs under baseURL inside dir
+func SetLocalSite(baseURL, dir string) error {
+	base, err := ParseURL(baseURL)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+	localSite = &LocalSite{Base: base, Dir: dir}
+	return nil
+}
+
+// Local returns the site set with SetLocalSite, nil when links are checked over the network
+func Local() *LocalSite {
+	return localSite
+}
+
+// relPath returns link's path below the base, ok is false for other sites
+func (s *LocalSite) relPath(link string) (string, bool) {
+	u, err := url.Parse(link)
+	if err != nil || !strings.EqualFold(u.Hostname(), s.Base.Hostname()) || !strings.HasPrefix(u.Path, s.Base.Path) {
+		return "", false
+	}
+	rel, err := url.PathUnescape(strings.TrimPrefix(u.Path, s.Base.Path))
+	if err != nil {
+		return "", false
+	}
+	return path.Clean("/" + rel), true
+}
+
+// Resolve returns the file serving link the way static hosts do: the file
+// itself, index.html for directories, and name.html for pretty URLs. exists is
+// false when link belongs to the site but no file serves it.
+func (s *LocalSite) Resolve(link string) (file string, local, exists bool) {
+	rel, ok := s.relPath(link)
+	if !ok {
+		return "", false, false
+	}
+
+	candidates := []string{rel, path.Join(rel, "index.html")}
+	if path.Ext(rel) == "" && rel != "/" {
+		candidates = append(candidates, rel+".html")
+	}
+	for _, candidate := range candidates {
+		file := filepath.Join(s.Dir, filepath.FromSlash(candidate))
+		if info, err := os.Stat(file); err == nil && !info.IsDir() {
+			return file, true, true
+		}
+	}
+	return filepath.Join(s.Dir, filepath.FromSlash(rel)), true, false
+}
+
+// URL returns the address a file of the site is served at, directory indexes
+// map to their directory
+func (s *LocalSite) URL(file string) (string, error) {
+	rel, err := filepath.Rel(s.Dir, file)
+	if err != nil {
+		return "", err
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == "index.html" {
+		rel = ""
+	} else if strings.HasSuffix(rel, "/index.html") {
+		rel = strings.TrimSuffix(rel, "index.html")
+	}
+	return s.Base.ResolveReference(&url.URL{Path: rel}).String(), nil
+}
+
+// readLocal reads at most maxBytes of a site file, guessing its type from the extension
+func readLocal(file string, maxBytes int64) ([]byte, string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, "", err
+	}
+	defer f.Close()
+	body, err := io.ReadAll(io.LimitReader(f, maxBytes))
+	return body, mime.TypeByExtension(filepath.Ext(file)), err
+}
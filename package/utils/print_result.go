@@ -3,6 +3,7 @@ package utils
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/MdSadiqMd/Scrape404/package/types"
@@ -22,6 +23,10 @@ func PrintResults(deadLinks []types.DeadLink, visitedLinks map[string]bool, visi
 	fmt.Printf("Total links checked: %d\n", len(visitedLinks))
 	fmt.Printf("Scan duration: %s\n", duration)
 	fmt.Printf("Dead links found: %d\n", len(deadLinks))
+	if linkCache != nil {
+		hits, misses := linkCache.Stats()
+		fmt.Printf("Cache hits: %d, misses: %d\n", hits, misses)
+	}
 
 	if len(deadLinks) == 0 {
 		titleColor.Println("\n✓ No dead links found!")
@@ -30,9 +35,9 @@ func PrintResults(deadLinks []types.DeadLink, visitedLinks map[string]bool, visi
 
 	titleColor.Printf("\n=== Dead Links (%d) ===\n\n", len(deadLinks))
 
-	fmt.Println("+----------------------+--------+----------+----------------------+")
-	fmt.Println("| Dead Link            | Status | Type     | Found On             |")
-	fmt.Println("+----------------------+--------+----------+----------------------+")
+	fmt.Println("+----------------------+--------+----------------+----------------------+----------------------+")
+	fmt.Println("| Dead Link            | Status | Type           | Found On             | Via                  |")
+	fmt.Println("+----------------------+--------+----------------+----------------------+----------------------+")
 
 	for _, link := range deadLinks {
 		statusText := "ERROR"
@@ -41,7 +46,142 @@ func PrintResults(deadLinks []types.DeadLink, visitedLinks map[string]bool, visi
 		}
 		deadLinkDisplay := truncateString(link.URL, 20)
 		foundOnDisplay := truncateString(link.FoundOn, 20)
-		fmt.Printf("| %-20s | %-6s | %-8s | %-20s |\n", deadLinkDisplay, statusText, link.Type, foundOnDisplay)
+		if link.PageCount > 1 {
+			more := fmt.Sprintf(" (+%d)", link.PageCount-1)
+			foundOnDisplay = truncateString(link.FoundOn, 20-len(more)) + more
+		}
+		viaDisplay := "-"
+		if link.Source != "" {
+			viaDisplay = truncateString(link.Source, 20)
+		}
+		fmt.Printf("| %-20s | %-6s | %-14s | %-20s | %-20s |\n", deadLinkDisplay, statusText, link.Type, foundOnDisplay, viaDisplay)
+	}
+	fmt.Println("+----------------------+--------+----------------+----------------------+----------------------+")
+
+	printLocations(deadLinks, titleColor)
+	printPages(deadLinks, titleColor)
+}
+
+// maxPrintedPages caps the pages listed per dead link in the terminal, reports list them all
+const maxPrintedPages = 5
+
+// printPages shows links found on several pages, then the dead links of every page
+func printPages(deadLinks []types.DeadLink, titleColor *color.Color) {
+	shared := false
+	for _, link := range deadLinks {
+		if link.PageCount <= 1 {
+			continue
+		}
+		if !shared {
+			titleColor.Printf("\n=== Dead Links on Several Pages ===\n\n")
+			shared = true
+		}
+		fmt.Printf("%s (%d pages)\n", displayLink(link.URL), link.PageCount)
+		for i, page := range link.Pages {
+			if i == maxPrintedPages {
+				fmt.Printf("    ... and %d more\n", link.PageCount-maxPrintedPages)
+				break
+			}
+			fmt.Printf("    %s\n", page)
+		}
+	}
+
+	titleColor.Printf("\n=== Dead Links by Page ===\n\n")
+	for _, page := range DeadLinksByPage(deadLinks) {
+		fmt.Printf("%s (%d)\n", page.Page, len(page.DeadLinks))
+		for _, link := range page.DeadLinks {
+			fmt.Printf("    %s\n", displayLink(link))
+		}
+	}
+}
+
+// printLocations lists where each dead link sits on its page, the table has no room for it
+func printLocations(deadLinks []types.DeadLink, titleColor *color.Color) {
+	located := 0
+	for _, link := range deadLinks {
+		if hasLocation(link.LinkContext) {
+			located++
+		}
+	}
+	if located == 0 {
+		return
+	}
+
+	titleColor.Printf("\n=== Dead Link Locations ===\n\n")
+	for _, link := range deadLinks {
+		if !hasLocation(link.LinkContext) {
+			continue
+		}
+		fmt.Printf("%s\n    first on %s", displayLink(link.URL), link.FoundOn)
+		if link.Line > 0 {
+			fmt.Printf(" line %d", link.Line)
+		}
+		fmt.Println()
+		if element := DescribeElement(link.LinkContext); element != "" {
+			fmt.Printf("    element: %s\n", element)
+		}
+		if link.Selector != "" {
+			fmt.Printf("    selector: %s\n", link.Selector)
+		}
+	}
+}
+
+// hasLocation reports whether ctx points at a place in the page, text alone doesn't
+func hasLocation(ctx types.LinkContext) bool {
+	return ctx.Selector != "" || ctx.Line > 0 || ctx.Attr != ""
+}
+
+// DescribeElement formats the tag, attribute and text of a link's element, like <a href> "Docs"
+func DescribeElement(ctx types.LinkContext) string {
+	var parts []string
+	if ctx.Tag != "" {
+		if ctx.Attr != "" {
+			parts = append(parts, fmt.Sprintf("<%s %s>", ctx.Tag, ctx.Attr))
+		} else {
+			parts = append(parts, fmt.Sprintf("<%s>", ctx.Tag))
+		}
+	}
+	if ctx.Text != "" {
+		parts = append(parts, strconv.Quote(ctx.Text))
+	}
+	return strings.Join(parts, " ")
+}
+
+func PrintJSErrors(jsErrors []types.JSError, titleColor, errorColor *color.Color) {
+	if len(jsErrors) == 0 {
+		titleColor.Println("\n✓ No JavaScript errors found!")
+		return
+	}
+
+	titleColor.Printf("\n=== JavaScript Errors (%d) ===\n\n", len(jsErrors))
+	for _, e := range jsErrors {
+		errorColor.Printf("[%s] %s\n", e.Kind, e.Message)
+		if e.Location != "" {
+			fmt.Printf("    at %s\n", e.Location)
+		}
+		fmt.Printf("    %d occurrence(s) on %d page(s), first on %s\n", e.Count, len(e.Pages), e.Pages[0])
+	}
+}
+
+// DeadLinksByPage groups dead links by the pages they appear on, in the order
+// pages were first seen. Pages beyond a link's capped list are not included.
+func DeadLinksByPage(deadLinks []types.DeadLink) []types.PageDeadLinks {
+	var pages []types.PageDeadLinks
+	index := make(map[string]int)
+	for _, link := range deadLinks {
+		referrers := link.Pages
+		if len(referrers) == 0 {
+			referrers = []string{link.FoundOn}
+		}
+		for _, page := range referrers {
+			i, ok := index[page]
+			if !ok {
+				i = len(pages)
+				index[page] = i
+				pages = append(pages, types.PageDeadLinks{Page: page})
+			}
+			pages[i].DeadLinks = append(pages[i].DeadLinks, link.URL)
+		}
 	}
-	fmt.Println("+----------------------+--------+----------+----------------------+")
+	return pages
 }
@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/MdSadiqMd/Scrape404/package/checkpoint"
+	"github.com/MdSadiqMd/Scrape404/package/types"
+	"github.com/fatih/color"
+)
+
+// startCheckpointing saves a snapshot every cfg.CheckpointInterval seconds and on
+// SIGINT/SIGTERM, where it also cancels the scan. The returned stop function
+// writes the final checkpoint, marked completed unless the scan was interrupted.
+func startCheckpointing(cfg types.ScanConfig, cancel context.CancelFunc, snapshot func() *checkpoint.Checkpoint, infoColor, errorColor *color.Color) func(completed bool) {
+	save := func(cp *checkpoint.Checkpoint) {
+		if err := checkpoint.Save(cp); err != nil {
+			errorColor.Printf("⚠️  Failed to save checkpoint: %s\n", err)
+		}
+	}
+
+	done := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	interval := time.Duration(cfg.CheckpointInterval) * time.Second
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				save(snapshot())
+			case <-signals:
+				save(snapshot())
+				infoColor.Printf("\nCheckpoint saved, finishing the pages in flight. Resume with: --resume %s\n", cfg.ScanID)
+				// A second signal kills the process the default way
+				signal.Stop(signals)
+				cancel()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func(completed bool) {
+		ticker.Stop()
+		signal.Stop(signals)
+		close(done)
+
+		cp := snapshot()
+		cp.Completed = completed
+		save(cp)
+	}
+}
+
+// frontierList flattens the pending-page map into checkpoint entries
+func frontierList(frontier map[string]int) []checkpoint.FrontierEntry {
+	entries := make([]checkpoint.FrontierEntry, 0, len(frontier))
+	for u, depth := range frontier {
+		entries = append(entries, checkpoint.FrontierEntry{URL: u, Depth: depth})
+	}
+	return entries
+}
+
+func copyVisited(visitedLinks map[string]bool) map[string]bool {
+	visited := make(map[string]bool, len(visitedLinks))
+	for k, v := range visitedLinks {
+		visited[k] = v
+	}
+	return visited
+}
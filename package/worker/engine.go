@@ -0,0 +1,372 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/MdSadiqMd/Scrape404/package/checkpoint"
+	"github.com/MdSadiqMd/Scrape404/package/extract"
+	"github.com/MdSadiqMd/Scrape404/package/report"
+	"github.com/MdSadiqMd/Scrape404/package/types"
+	"github.com/MdSadiqMd/Scrape404/package/utils"
+	"github.com/fatih/color"
+)
+
+// ErrInterrupted is returned by Scan when SIGINT or SIGTERM stopped the crawl.
+// The partial results are reported and the checkpoint can be resumed.
+var ErrInterrupted = errors.New("scan interrupted")
+
+// pageType is reported for crawled pages that failed to load
+const pageType = "page"
+
+// Scan crawls cfg.URL with the backend the config selects. Depth counts link
+// hops from the start page, which is depth 0. It returns an error when the
+// JavaScript error thresholds are exceeded.
+func Scan(cfg types.ScanConfig, resume *checkpoint.Checkpoint) error {
+	urlStr, maxDepth, delayMs, parallelism := cfg.URL, cfg.MaxDepth, cfg.DelayMs, cfg.Parallelism
+
+	titleColor := color.New(color.FgCyan, color.Bold)
+	successColor := color.New(color.FgGreen)
+	errorColor := color.New(color.FgRed)
+	warningColor := color.New(color.FgYellow)
+	infoColor := color.New(color.FgBlue)
+
+	switch {
+	case cfg.SourcesDir != "":
+		titleColor.Println("\n=== Dead Link Checker (Sources Mode) ===")
+	case cfg.SourceDir != "":
+		titleColor.Println("\n=== Dead Link Checker (Filesystem Mode) ===")
+	case cfg.UsePlaywright:
+		titleColor.Println("\n=== Dead Link Checker (Playwright Mode) ===")
+	case cfg.Hybrid:
+		titleColor.Println("\n=== Dead Link Checker (Hybrid Mode) ===")
+	default:
+		titleColor.Println("\n=== Dead Link Checker ===")
+	}
+	infoColor.Printf("Starting scan for: %s\n", urlStr)
+	infoColor.Printf("Max depth: %d, Delay: %dms, Parallel workers: %d\n", maxDepth, delayMs, parallelism)
+	infoColor.Printf("Scan ID: %s\n\n", cfg.ScanID)
+
+	baseURL, err := utils.ParseURL(urlStr)
+	if err != nil {
+		errorColor.Printf("Error parsing URL: %s\n", err)
+		return nil
+	}
+	if baseURL.Hostname() != "" {
+		infoColor.Printf("Domain to scan: %s\n", baseURL.Hostname())
+	}
+
+	f, err := newFetcher(cfg, resume, infoColor, errorColor)
+	if err != nil {
+		errorColor.Printf("Error: %s\n", err)
+		return nil
+	}
+	defer f.Close()
+	seeds, seeded := f.(seeder)
+
+	var mu sync.Mutex
+	visitedLinks := make(map[string]bool)
+	deadLinks := make([]types.DeadLink, 0)
+	// Dead links by the checked URL that found them, to add the pages it appears on later
+	deadBy := make(map[string][]int)
+	visitedPages := 0
+	startTime := time.Now()
+	// Pages queued or in flight, keyed by URL with crawl depth as value
+	frontier := make(map[string]int)
+	var elapsed time.Duration
+	if resume != nil {
+		visitedLinks = resume.VisitedLinks
+		deadLinks = resume.DeadLinks
+		visitedPages = resume.VisitedPages
+		elapsed = time.Duration(resume.Elapsed) * time.Millisecond
+		for i, link := range deadLinks {
+			deadBy[checkedURL(link)] = append(deadBy[checkedURL(link)], i)
+		}
+		for _, entry := range resume.Frontier {
+			frontier[entry.URL] = entry.Depth
+		}
+	} else {
+		frontier[urlStr] = 0
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stopCheckpointing := startCheckpointing(cfg, cancel, func() *checkpoint.Checkpoint {
+		mu.Lock()
+		defer mu.Unlock()
+		cp := &checkpoint.Checkpoint{
+			Config:       cfg,
+			VisitedLinks: copyVisited(visitedLinks),
+			DeadLinks:    append([]types.DeadLink(nil), deadLinks...),
+			VisitedPages: visitedPages,
+			Frontier:     frontierList(frontier),
+			Elapsed:      (elapsed + time.Since(startTime)).Milliseconds(),
+		}
+		if r, ok := f.(jsErrorReporter); ok {
+			cp.JSErrors = r.JSErrors()
+		}
+		if r, ok := f.(evidenceReporter); ok {
+			cp.Evidence = r.Evidence()
+		}
+		return cp
+	}, infoColor, errorColor)
+
+	queue := newWorkQueue()
+	go func() {
+		<-ctx.Done()
+		queue.Stop()
+	}()
+
+	// crawl queues a same-site page found on referrer, the caller holds mu.
+	// Seeded backends are walked file by file instead.
+	crawl := func(link, referrer string, depth int) {
+		if !seeded && utils.IsWebLink(link) && utils.SameHost(link, urlStr) && depth <= maxDepth {
+			frontier[link] = depth
+			queue.Push(pageJob{URL: link, Depth: depth, Referrer: referrer})
+		}
+	}
+
+	// recordDeadPage reports a crawled page that failed to load, unless the
+	// check of its link already did
+	recordDeadPage := func(job pageJob, status int) {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(deadBy[job.URL]) > 0 {
+			return
+		}
+		foundOn := job.Referrer
+		if foundOn == "" {
+			foundOn = job.URL
+		}
+		deadLinks = append(deadLinks, types.DeadLink{URL: job.URL, FoundOn: foundOn, Type: pageType, StatusCode: status})
+		trackDeadLinks(deadLinks, len(deadLinks)-1, deadBy)
+	}
+
+	visit := func(job pageJob) {
+		url, depth := job.URL, job.Depth
+		defer func() {
+			mu.Lock()
+			delete(frontier, url)
+			mu.Unlock()
+		}()
+
+		if depth > maxDepth {
+			return
+		}
+
+		mu.Lock()
+		visitedPages++
+		pageNum := visitedPages
+		mu.Unlock()
+
+		infoColor.Printf("🔍 [%d] Visiting: %s\n", pageNum, url)
+		page, err := f.Fetch(job)
+		if err != nil {
+			errorColor.Printf("⚠️  Error visiting %s: %s\n", url, err)
+			return
+		}
+
+		foundDead := false
+		defer func() {
+			if page.done != nil {
+				page.done(foundDead)
+			}
+		}()
+
+		switch status := page.StatusCode; {
+		case status == 403 || status == 429:
+			warningColor.Printf("⚠️  SKIPPING %s (Blocked: %d - Likely Cloudflare protection)\n", url, status)
+			return
+		case status >= 400:
+			errorColor.Printf("⚠️  Failed to load %s (Status: %d)\n", url, status)
+			recordDeadPage(job, status)
+			foundDead = true
+			return
+		}
+		successColor.Printf("✓ Page loaded: %s (Status: %d)\n", url, page.StatusCode)
+
+		// Redirects may leave the site, whose pages are not ours to check
+		if page.FinalURL != "" && !utils.SameHost(page.FinalURL, urlStr) {
+			infoColor.Printf("  %s redirected off-site to %s, not extracting\n", url, page.FinalURL)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		deadBefore := len(deadLinks)
+		defer func() {
+			trackDeadLinks(deadLinks, deadBefore, deadBy)
+			foundDead = len(deadLinks) > deadBefore
+		}()
+
+		// reference reports whether link still needs checking. Links checked
+		// before add foundOn to the pages of their dead links instead.
+		referenced := make(map[string]bool)
+		reference := func(link, foundOn string) bool {
+			if key := foundOn + " " + link; !referenced[key] {
+				referenced[key] = true
+				if visitedLinks[link] {
+					trackDeadLinks(deadLinks, deadBefore, deadBy)
+					for _, i := range deadBy[link] {
+						addReferrer(&deadLinks[i], foundOn)
+					}
+				}
+			}
+			if visitedLinks[link] {
+				return false
+			}
+			visitedLinks[link] = true
+			return true
+		}
+
+		if page.NotFound {
+			foundOn := job.Referrer
+			if foundOn == "" {
+				foundOn = url
+			}
+			deadLinks = append(deadLinks, types.DeadLink{URL: url, FoundOn: foundOn, Type: spaRouteType})
+			errorColor.Printf("❌ Dead %s found: %s (not found view)\n", spaRouteType, url)
+			return
+		}
+
+		// Subresources the backend already fetched need no second request
+		fetched := make(map[string]networkResult)
+		for _, result := range page.Requests {
+			if !result.Blocked {
+				fetched[result.URL] = result
+			}
+		}
+
+		for _, frame := range page.Frames {
+			// Links inside same-origin iframes are reported as found on the frame
+			for _, item := range frame.Items {
+				for _, ref := range item.Rule.Refs(item.Value) {
+					link := extract.Resolve(frame.Base, ref.Value)
+					if extract.Skip(link) || !reference(link, frame.URL) {
+						continue
+					}
+					checked := len(deadLinks)
+					if result, ok := fetched[link]; ok {
+						utils.RecordLink(link, frame.URL, ref.Type, result.Status, result.Failure, &deadLinks, infoColor, successColor, errorColor)
+						checkEmbedded(link, frame.URL, ref, cfg, visitedLinks, &deadLinks, infoColor, successColor, errorColor)
+					} else {
+						checkResource(link, frame.URL, ref, cfg, visitedLinks, &deadLinks, infoColor, successColor, errorColor)
+					}
+					addLinkContext(deadLinks[checked:], link, item.Context)
+					// A dead page was already requested by its check, there is nothing to crawl
+					if item.Rule.Crawl && !foundDeadLink(deadLinks[checked:], link) {
+						crawl(link, frame.URL, depth+1)
+					}
+				}
+			}
+		}
+
+		// Requests with no matching element, such as XHR/fetch calls, fonts and injected scripts
+		for _, result := range page.Requests {
+			if !reference(result.URL, url) {
+				continue
+			}
+			if result.Blocked {
+				utils.CheckLink(result.URL, url, result.ResourceType, &deadLinks, infoColor, successColor, errorColor)
+				continue
+			}
+			utils.RecordLink(result.URL, url, result.ResourceType, result.Status, result.Failure, &deadLinks, infoColor, successColor, errorColor)
+		}
+
+		for _, route := range page.Routes {
+			if extract.Skip(route) || !reference(route, url) {
+				continue
+			}
+			checked := len(deadLinks)
+			utils.CheckLink(route, url, spaRouteType, &deadLinks, infoColor, successColor, errorColor)
+			if !foundDeadLink(deadLinks[checked:], route) {
+				crawl(route, url, depth+1)
+			}
+		}
+	}
+
+	// Start crawling, or pick up the pending pages of a previous run
+	if resume == nil && seeded {
+		pages, err := seeds.Seeds()
+		if err != nil {
+			errorColor.Printf("Error listing files: %s\n", err)
+		}
+		infoColor.Printf("Checking %d files\n", len(pages))
+		mu.Lock()
+		delete(frontier, urlStr)
+		for _, page := range pages {
+			frontier[page] = 0
+			queue.Push(pageJob{URL: page, Depth: 0})
+		}
+		mu.Unlock()
+	} else if resume == nil {
+		queue.Push(pageJob{URL: urlStr, Depth: 0})
+	} else {
+		infoColor.Printf("Resuming scan %s with %d pending pages\n", cfg.ScanID, len(resume.Frontier))
+		for _, entry := range resume.Frontier {
+			queue.Push(pageJob{URL: entry.URL, Depth: entry.Depth})
+		}
+	}
+
+	delay := time.Duration(delayMs) * time.Millisecond
+	limiter := newHostLimiter(parallelism, delay, delay/2)
+	var wg sync.WaitGroup
+	for i := 0; i < max(parallelism, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				job, ok := queue.Pop()
+				if !ok {
+					return
+				}
+				release := limiter.Acquire(job.URL)
+				visit(job)
+				release()
+				queue.Done()
+			}
+		}()
+	}
+	wg.Wait()
+	interrupted := ctx.Err() != nil
+	stopCheckpointing(!interrupted)
+
+	totalTime := (elapsed + time.Since(startTime)).Round(time.Second)
+	utils.PrintResults(deadLinks, visitedLinks, visitedPages, totalTime, titleColor, errorColor)
+
+	result := &report.Report{
+		ScanID:       cfg.ScanID,
+		URL:          urlStr,
+		Mode:         f.Mode(),
+		Duration:     totalTime.String(),
+		PagesVisited: visitedPages,
+		LinksChecked: len(visitedLinks),
+		DeadLinks:    deadLinks,
+		DeadByPage:   utils.DeadLinksByPage(deadLinks),
+	}
+	if r, ok := f.(evidenceReporter); ok {
+		result.Evidence = r.Evidence()
+	}
+	r, reportsJS := f.(jsErrorReporter)
+	if reportsJS {
+		result.JSErrors = r.JSErrors()
+		utils.PrintJSErrors(result.JSErrors, titleColor, errorColor)
+	}
+	writeReport(result, cfg, infoColor, errorColor)
+
+	if interrupted {
+		return ErrInterrupted
+	}
+	if reportsJS {
+		if err := checkJSErrorThresholds(result.JSErrors, cfg); err != nil {
+			return err
+		}
+	}
+	if cfg.FailOnDeadLinks && len(deadLinks) > 0 {
+		return fmt.Errorf("%d dead links found", len(deadLinks))
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package worker
+
+import (
+	neturl "net/url"
+
+	"github.com/MdSadiqMd/Scrape404/package/checkpoint"
+	"github.com/MdSadiqMd/Scrape404/package/extract"
+	"github.com/MdSadiqMd/Scrape404/package/types"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/fatih/color"
+)
+
+// fetcher is a crawl backend. It loads one page and extracts its references,
+// while the engine owns scheduling, scoping, deduplication, checking and
+// reporting. A new backend implements fetcher and is picked in newFetcher.
+type fetcher interface {
+	// Mode names the backend in the scan title and report
+	Mode() string
+	// Fetch may be called from several workers at once
+	Fetch(job pageJob) (*fetchedPage, error)
+	Close()
+}
+
+// Backends that run JavaScript implement these to surface what they collected
+type jsErrorReporter interface {
+	JSErrors() []types.JSError
+}
+
+type evidenceReporter interface {
+	Evidence() []types.PageEvidence
+}
+
+// Backends that know all their pages up front implement seeder, the engine
+// then checks exactly those pages instead of crawling from cfg.URL
+type seeder interface {
+	Seeds() ([]string, error)
+}
+
+type frameItem struct {
+	Rule  extract.Rule
+	Value string
+	// Context locates the element, it is copied onto dead links found in Value
+	Context types.LinkContext
+}
+
+// pageFrame is the top-level document of a page or one of its same-origin iframes
+type pageFrame struct {
+	URL   string
+	Base  *neturl.URL
+	Items []frameItem
+}
+
+type fetchedPage struct {
+	URL string
+	// FinalURL is where redirects ended, pages that left the site are not extracted
+	FinalURL   string
+	StatusCode int
+	Frames     []pageFrame
+	// Requests the backend already made while loading the page, these need no second check
+	Requests []networkResult
+	// Routes are client-side routes discovered by interacting with the page
+	Routes []string
+	// NotFound marks pages rendering the configured client-side not found view
+	NotFound bool
+
+	// document is the static HTML, kept for hybrid rendering heuristics
+	document *goquery.Document
+	// done releases backend resources once the engine has checked the page,
+	// deadLinks tells whether checking it found new dead links
+	done func(deadLinks bool)
+}
+
+func newFetcher(cfg types.ScanConfig, resume *checkpoint.Checkpoint, infoColor, errorColor *color.Color) (fetcher, error) {
+	switch {
+	case cfg.SourcesDir != "":
+		return newSourceFetcher(cfg.SourcesDir)
+	case cfg.SourceDir != "":
+		files, err := newFileFetcher()
+		if err != nil || !cfg.Hybrid {
+			return files, err
+		}
+		h, err := newHybridFetcher(cfg, files, resume, infoColor, errorColor)
+		if err != nil {
+			return nil, err
+		}
+		return &localHybridFetcher{hybridFetcher: h, files: files}, nil
+	case cfg.UsePlaywright:
+		return newBrowserFetcher(cfg, resume, max(cfg.Parallelism, 1), infoColor, errorColor)
+	case cfg.Hybrid:
+		static, err := newCollyFetcher(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return newHybridFetcher(cfg, static, resume, infoColor, errorColor)
+	}
+	return newCollyFetcher(cfg)
+}
@@ -0,0 +1,152 @@
+package worker
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"time"
+
+	"github.com/MdSadiqMd/Scrape404/package/extract"
+	"github.com/MdSadiqMd/Scrape404/package/types"
+	"github.com/MdSadiqMd/Scrape404/package/utils"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly"
+)
+
+// collyFetcher downloads pages over plain HTTP and extracts references from the
+// static HTML
+type collyFetcher struct {
+	collector *colly.Collector
+	// maxPDFSize enables checking the links of crawled PDFs served without a .pdf path
+	maxPDFSize int
+}
+
+func newCollyFetcher(cfg types.ScanConfig) (*collyFetcher, error) {
+	// The engine deduplicates and schedules, colly only performs the requests
+	c := colly.NewCollector(
+		colly.UserAgent(cfg.UserAgent),
+		colly.AllowURLRevisit(),
+		colly.ParseHTTPErrorResponse(),
+	)
+	c.SetRequestTimeout(time.Duration(cfg.TimeoutSec) * time.Second)
+
+	if rt := utils.Transport(); rt != nil {
+		c.WithTransport(rt)
+	}
+
+	creds := utils.Credentials()
+	for _, ck := range creds.Cookies() {
+		cookieURL, cookie := ck.HTTPCookie()
+		if err := c.SetCookies(cookieURL.String(), []*http.Cookie{cookie}); err != nil {
+			return nil, err
+		}
+	}
+	c.OnRequest(func(r *colly.Request) {
+		for name, value := range creds.HeadersFor(r.URL) {
+			r.Headers.Set(name, value)
+		}
+	})
+	// net/http copies the headers of the previous request on redirects, including
+	// custom credential headers, so they are re-scoped for the new host
+	c.RedirectHandler = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return http.ErrUseLastResponse
+		}
+		creds.Apply(req)
+		return nil
+	}
+
+	c.OnResponse(func(r *colly.Response) {
+		r.Ctx.Put("response", r)
+	})
+	f := &collyFetcher{collector: c}
+	if cfg.CheckPDFs {
+		f.maxPDFSize = cfg.MaxPDFSizeMB << 20
+	}
+	return f, nil
+}
+
+func (f *collyFetcher) Mode() string {
+	return "http"
+}
+
+func (f *collyFetcher) Fetch(job pageJob) (*fetchedPage, error) {
+	ctx := colly.NewContext()
+	err := f.collector.Request("GET", job.URL, nil, ctx, nil)
+	resp, _ := ctx.GetAny("response").(*colly.Response)
+	if resp == nil {
+		if err == nil {
+			err = errors.New("no response")
+		}
+		return nil, err
+	}
+
+	page := &fetchedPage{URL: job.URL, FinalURL: resp.Request.URL.String(), StatusCode: resp.StatusCode}
+	if resp.StatusCode >= 400 {
+		return page, nil
+	}
+	contentType := resp.Headers.Get("Content-Type")
+	// PDFs with a .pdf path are already checked where they are linked from
+	if f.maxPDFSize > 0 && !extract.IsPDF(job.URL) && len(resp.Body) <= f.maxPDFSize && extract.IsPDFContent(contentType, resp.Body) {
+		page.Frames = []pageFrame{pdfFrame(resp.Body, job.URL, resp.Request.URL)}
+		return page, nil
+	}
+	if !strings.Contains(contentType, "html") {
+		return page, nil
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(resp.Body))
+	if err != nil {
+		return nil, err
+	}
+	page.document = doc
+	page.Frames = []pageFrame{staticFrame(doc, resp.Body, job.URL, resp.Request.URL)}
+	return page, nil
+}
+
+func (f *collyFetcher) Close() {}
+
+// pdfFrame holds the URI annotations of a PDF, found on the PDF itself
+func pdfFrame(data []byte, pageURL string, base *neturl.URL) pageFrame {
+	frame := pageFrame{URL: pageURL, Base: base}
+	for _, link := range extract.PDFLinks(data, pageURL) {
+		frame.Items = append(frame.Items, frameItem{Rule: extract.Rule{Type: extract.PDFLinkType}, Value: link})
+	}
+	return frame
+}
+
+// staticFrame applies the extraction rules to a parsed document, resolving
+// against its <base href> when present. data is the HTML doc was parsed from,
+// used to find the line of each element.
+func staticFrame(doc *goquery.Document, data []byte, pageURL string, base *neturl.URL) pageFrame {
+	if href, ok := doc.Find("base[href]").First().Attr("href"); ok {
+		if resolved, err := base.Parse(strings.TrimSpace(href)); err == nil {
+			base = resolved
+		}
+	}
+
+	lines := extract.NewSourceLines(data)
+	frame := pageFrame{URL: pageURL, Base: base}
+	for _, rule := range extract.Rules {
+		doc.Find(rule.Selector).Each(func(_ int, s *goquery.Selection) {
+			value := s.Text()
+			if rule.Attr != "" {
+				value, _ = s.Attr(rule.Attr)
+			}
+			if value == "" {
+				return
+			}
+			node := s.Get(0)
+			frame.Items = append(frame.Items, frameItem{Rule: rule, Value: value, Context: types.LinkContext{
+				Text:     extract.ElementText(node),
+				Selector: extract.CSSPath(node),
+				Line:     lines.Line(node, rule.Attr, value),
+				Tag:      node.Data,
+				Attr:     rule.Attr,
+			}})
+		})
+	}
+	return frame
+}
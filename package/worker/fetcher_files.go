@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MdSadiqMd/Scrape404/package/utils"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// fileFetcher reads the pages of a built static site from disk
+type fileFetcher struct {
+	site *utils.LocalSite
+}
+
+func newFileFetcher() (*fileFetcher, error) {
+	site := utils.Local()
+	if site == nil {
+		return nil, fmt.Errorf("no local site directory configured")
+	}
+	return &fileFetcher{site: site}, nil
+}
+
+func (f *fileFetcher) Mode() string {
+	return "filesystem"
+}
+
+func (f *fileFetcher) Fetch(job pageJob) (*fetchedPage, error) {
+	file, local, exists := f.site.Resolve(job.URL)
+	if !local {
+		return nil, fmt.Errorf("%s is outside %s", job.URL, f.site.Base)
+	}
+	page := &fetchedPage{URL: job.URL, FinalURL: job.URL, StatusCode: http.StatusOK}
+	if !exists {
+		page.StatusCode = http.StatusNotFound
+		return page, nil
+	}
+	if ext := strings.ToLower(filepath.Ext(file)); ext != ".html" && ext != ".htm" {
+		return page, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	base, _ := neturl.Parse(job.URL)
+	page.document = doc
+	page.Frames = []pageFrame{staticFrame(doc, data, job.URL, base)}
+	return page, nil
+}
+
+func (f *fileFetcher) Seeds() ([]string, error) {
+	return sitePages(f.site)
+}
+
+func (f *fileFetcher) Close() {}
+
+// sitePages returns the URLs of every HTML file of the local site
+func sitePages(site *utils.LocalSite) ([]string, error) {
+	var pages []string
+	err := filepath.WalkDir(site.Dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if ext := strings.ToLower(filepath.Ext(file)); ext != ".html" && ext != ".htm" {
+			return nil
+		}
+		page, err := site.URL(file)
+		if err != nil {
+			return err
+		}
+		pages = append(pages, page)
+		return nil
+	})
+	return pages, err
+}
@@ -0,0 +1,152 @@
+package worker
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/MdSadiqMd/Scrape404/package/checkpoint"
+	"github.com/MdSadiqMd/Scrape404/package/extract"
+	"github.com/MdSadiqMd/Scrape404/package/types"
+	"github.com/fatih/color"
+)
+
+// hybridFetcher fetches over HTTP, or from disk for a local site, and renders
+// with Playwright only the pages whose static HTML looks incomplete or that
+// match a render pattern. The browser is started for the first page that needs
+// it and renders one page at a time.
+type hybridFetcher struct {
+	static fetcher
+	force  []*regexp.Regexp
+
+	cfg        types.ScanConfig
+	resume     *checkpoint.Checkpoint
+	infoColor  *color.Color
+	errorColor *color.Color
+
+	mu       sync.Mutex
+	started  bool
+	startErr error
+	renderer *browserFetcher
+}
+
+func newHybridFetcher(cfg types.ScanConfig, static fetcher, resume *checkpoint.Checkpoint, infoColor, errorColor *color.Color) (*hybridFetcher, error) {
+	h := &hybridFetcher{static: static, cfg: cfg, resume: resume, infoColor: infoColor, errorColor: errorColor}
+	for _, pattern := range cfg.RenderURLPatterns {
+		re, err := globRegexp(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid render pattern %q: %w", pattern, err)
+		}
+		h.force = append(h.force, re)
+	}
+	return h, nil
+}
+
+func (h *hybridFetcher) Mode() string {
+	return "hybrid"
+}
+
+// forced reports whether url matches one of the patterns that always render
+func (h *hybridFetcher) forced(url string) bool {
+	for _, re := range h.force {
+		if re.MatchString(url) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *hybridFetcher) browser() (*browserFetcher, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.started {
+		h.started = true
+		h.renderer, h.startErr = newBrowserFetcher(h.cfg, h.resume, 1, h.infoColor, h.errorColor)
+	}
+	return h.renderer, h.startErr
+}
+
+// current returns the browser if it was started, the checkpoint goroutine
+// reads it while workers may be starting it
+func (h *hybridFetcher) current() *browserFetcher {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.renderer
+}
+
+func (h *hybridFetcher) Fetch(job pageJob) (*fetchedPage, error) {
+	page, err := h.static.Fetch(job)
+	if err != nil || page.document == nil {
+		return page, err
+	}
+
+	reason := extract.RenderReason(page.document.Selection)
+	if h.forced(job.URL) {
+		reason = "matches a render pattern"
+	}
+	if reason == "" {
+		return page, nil
+	}
+
+	h.infoColor.Printf("🖥  Rendering %s with Playwright (%s)\n", job.URL, reason)
+	renderer, err := h.browser()
+	if err != nil {
+		h.errorColor.Printf("⚠️  Could not render %s: %s\n", job.URL, err)
+		return page, nil
+	}
+	rendered, err := renderer.Fetch(job)
+	if err != nil {
+		h.errorColor.Printf("⚠️  Could not render %s: %s\n", job.URL, err)
+		return page, nil
+	}
+
+	// Static frames stay first, the engine skips references it has already seen
+	page.Frames = append(page.Frames, rendered.Frames...)
+	page.Requests = rendered.Requests
+	page.Routes = rendered.Routes
+	page.NotFound = rendered.NotFound
+	page.done = rendered.done
+	return page, nil
+}
+
+func (h *hybridFetcher) JSErrors() []types.JSError {
+	renderer := h.current()
+	if renderer == nil {
+		if h.resume != nil {
+			return h.resume.JSErrors
+		}
+		return nil
+	}
+	return renderer.JSErrors()
+}
+
+func (h *hybridFetcher) Evidence() []types.PageEvidence {
+	renderer := h.current()
+	if renderer == nil {
+		if h.resume != nil {
+			return h.resume.Evidence
+		}
+		return nil
+	}
+	return renderer.Evidence()
+}
+
+func (h *hybridFetcher) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.renderer != nil {
+		h.renderer.Close()
+	}
+	h.static.Close()
+}
+
+// localHybridFetcher renders the pages of a local site that need JavaScript,
+// the browser loads them from disk too
+type localHybridFetcher struct {
+	*hybridFetcher
+	files *fileFetcher
+}
+
+func (f *localHybridFetcher) Seeds() ([]string, error) {
+	return f.files.Seeds()
+}
@@ -0,0 +1,227 @@
+package worker
+
+import (
+	"fmt"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/MdSadiqMd/Scrape404/package/auth"
+	"github.com/MdSadiqMd/Scrape404/package/checkpoint"
+	"github.com/MdSadiqMd/Scrape404/package/report"
+	"github.com/MdSadiqMd/Scrape404/package/types"
+	"github.com/fatih/color"
+	"github.com/playwright-community/playwright-go"
+)
+
+// browserFetcher renders pages in Playwright using a fixed pool of contexts,
+// each reusing its page across URLs
+type browserFetcher struct {
+	cfg        types.ScanConfig
+	errorColor *color.Color
+
+	pw       *playwright.Playwright
+	browser  playwright.Browser
+	slots    chan *pageSlot
+	jsErrors *jsErrorLog
+	evidence *evidenceLog
+}
+
+func newBrowserFetcher(cfg types.ScanConfig, resume *checkpoint.Checkpoint, size int, infoColor, errorColor *color.Color) (*browserFetcher, error) {
+	var restoredErrors []types.JSError
+	var restoredEvidence []types.PageEvidence
+	if resume != nil {
+		restoredErrors, restoredEvidence = resume.JSErrors, resume.Evidence
+	}
+	blocker, err := newRequestBlocker(cfg)
+	if err != nil {
+		return nil, err
+	}
+	scanDir := report.Dir(cfg.ResultsDir, cfg.ScanID)
+	if err := os.MkdirAll(scanDir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating results directory: %w", err)
+	}
+
+	pw, browser, err := launchBrowser(cfg, infoColor)
+	if err != nil {
+		return nil, err
+	}
+	f := &browserFetcher{
+		cfg:        cfg,
+		errorColor: errorColor,
+		pw:         pw,
+		browser:    browser,
+		slots:      make(chan *pageSlot, size),
+		jsErrors:   newJSErrorLog(restoredErrors),
+		evidence:   newEvidenceLog(restoredEvidence),
+	}
+
+	storageState, err := loadStorageState(browser, cfg, infoColor)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("preparing login state: %w", err)
+	}
+	contextOptions := newContextOptions(cfg, storageState)
+	for i := 0; i < size; i++ {
+		harPath := ""
+		if cfg.CaptureHAR {
+			harPath = filepath.Join(scanDir, fmt.Sprintf("worker-%d.har.tmp", i))
+		}
+		slot, err := newPageSlot(browser, contextOptions, cfg, f.jsErrors, blocker, harPath)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("creating browser context: %w", err)
+		}
+		f.slots <- slot
+	}
+	return f, nil
+}
+
+func (f *browserFetcher) Mode() string {
+	return "playwright"
+}
+
+// Fetch holds a pooled page until the engine calls done, so evidence can be
+// captured once the page's links were checked
+func (f *browserFetcher) Fetch(job pageJob) (*fetchedPage, error) {
+	url, cfg := job.URL, f.cfg
+	slot := <-f.slots
+
+	// Pages with new dead links or JavaScript errors get their evidence saved
+	release := func(broken bool) {
+		if broken && cfg.CaptureScreenshots {
+			if shot, err := captureScreenshot(slot.page, cfg.ResultsDir, url); err != nil {
+				f.errorColor.Printf("⚠️  Failed to screenshot %s: %s\n", url, err)
+			} else {
+				f.evidence.add(types.PageEvidence{Page: url, Screenshot: shot})
+			}
+		}
+		if cfg.CaptureHAR {
+			dest, rel := "", ""
+			if broken {
+				rel = evidenceFile(url, "har")
+				dest = filepath.Join(cfg.ResultsDir, rel)
+			}
+			if err := slot.FinishHAR(dest); err != nil {
+				f.errorColor.Printf("⚠️  Failed to save HAR for %s: %s\n", url, err)
+			} else if broken {
+				f.evidence.add(types.PageEvidence{Page: url, HAR: rel})
+			}
+		}
+		f.slots <- slot
+	}
+
+	if err := slot.Begin(url); err != nil {
+		f.slots <- slot
+		return nil, fmt.Errorf("preparing browser page: %w", err)
+	}
+	page, requests := slot.page, slot.requests
+
+	resp, err := page.Goto(url, playwright.PageGotoOptions{
+		WaitUntil: waitUntilState(cfg.WaitUntil),
+	})
+	if err != nil {
+		release(false)
+		return nil, err
+	}
+
+	fetched := &fetchedPage{URL: url, FinalURL: page.URL(), StatusCode: 200}
+	fetched.done = func(deadLinks bool) {
+		release(deadLinks || f.jsErrors.OnPage(url))
+	}
+	if resp != nil {
+		fetched.StatusCode = resp.Status()
+	}
+	if fetched.StatusCode >= 400 {
+		return fetched, nil
+	}
+
+	if cfg.WaitForSelector != "" {
+		if _, err := page.WaitForSelector(cfg.WaitForSelector); err != nil {
+			f.errorColor.Printf("⚠️  %s never matched on %s, extracting anyway\n", cfg.WaitForSelector, url)
+		}
+	}
+
+	// SPAs answer every route with 200 and render their own not found view
+	if cfg.NotFoundSelector != "" {
+		if el, err := page.QuerySelector(cfg.NotFoundSelector); err == nil && el != nil {
+			fetched.NotFound = true
+			return fetched, nil
+		}
+	}
+
+	if fetched.Frames, err = extractFrames(page, url); err != nil {
+		release(false)
+		return nil, fmt.Errorf("extracting links: %w", err)
+	}
+	fetched.Requests = requests.Results()
+
+	// Client-side routes reachable only through click handlers
+	if cfg.ExploreSPA {
+		fetched.Routes = exploreRoutes(page, url, cfg.ExploreSkip, cfg.ExploreSkipSelectors)
+	}
+	return fetched, nil
+}
+
+func (f *browserFetcher) JSErrors() []types.JSError {
+	return f.jsErrors.Errors()
+}
+
+func (f *browserFetcher) Evidence() []types.PageEvidence {
+	return f.evidence.Evidence()
+}
+
+func (f *browserFetcher) Close() {
+	for len(f.slots) > 0 {
+		(<-f.slots).Close()
+	}
+	f.browser.Close()
+	f.pw.Stop()
+}
+
+// applyCredentials imports cookies into the context and injects per-host auth
+// headers through request interception, so they are only sent to their own host
+func applyCredentials(context playwright.BrowserContext, creds *auth.Credentials) error {
+	if cookies := creds.Cookies(); len(cookies) > 0 {
+		optional := make([]playwright.OptionalCookie, 0, len(cookies))
+		for _, ck := range cookies {
+			cookie := playwright.OptionalCookie{
+				Name:     ck.Name,
+				Value:    ck.Value,
+				Domain:   playwright.String(ck.Domain),
+				Path:     playwright.String(ck.Path),
+				Secure:   playwright.Bool(ck.Secure),
+				HttpOnly: playwright.Bool(ck.HttpOnly),
+			}
+			if ck.Expires > 0 {
+				cookie.Expires = playwright.Float(float64(ck.Expires))
+			}
+			optional = append(optional, cookie)
+		}
+		if err := context.AddCookies(optional); err != nil {
+			return err
+		}
+	}
+
+	if !creds.HasHeaders() {
+		return nil
+	}
+	return context.Route("**/*", func(route playwright.Route) {
+		reqURL, err := neturl.Parse(route.Request().URL())
+		if err != nil {
+			route.Continue()
+			return
+		}
+		extra := creds.HeadersFor(reqURL)
+		if len(extra) == 0 {
+			route.Continue()
+			return
+		}
+
+		headers := route.Request().Headers()
+		for name, value := range extra {
+			headers[name] = value
+		}
+		route.Continue(playwright.RouteContinueOptions{Headers: headers})
+	})
+}
@@ -0,0 +1,128 @@
+package worker
+
+import (
+	"fmt"
+	"io/fs"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/MdSadiqMd/Scrape404/package/extract"
+	"github.com/MdSadiqMd/Scrape404/package/types"
+)
+
+// sourceFetcher reads Markdown and reStructuredText files of a repository.
+// Each source line with links becomes a frame found on "path:line", and
+// relative links resolve to file:// URLs checked on disk.
+type sourceFetcher struct {
+	root    string
+	rootURL *neturl.URL
+}
+
+func newSourceFetcher(root string) (*sourceFetcher, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(root); err != nil {
+		return nil, err
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+	return &sourceFetcher{root: root, rootURL: fileURL(root + string(filepath.Separator))}, nil
+}
+
+func fileURL(path string) *neturl.URL {
+	return &neturl.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+}
+
+func isSourceFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".mdx", ".markdown", ".rst":
+		return true
+	}
+	return false
+}
+
+func (f *sourceFetcher) Mode() string {
+	return "sources"
+}
+
+// Seeds returns every source file below the root, skipping hidden directories
+// and dependencies
+func (f *sourceFetcher) Seeds() ([]string, error) {
+	var seeds []string
+	err := filepath.WalkDir(f.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != f.root && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules" || d.Name() == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isSourceFile(path) {
+			seeds = append(seeds, fileURL(path).String())
+		}
+		return nil
+	})
+	return seeds, err
+}
+
+func (f *sourceFetcher) Fetch(job pageJob) (*fetchedPage, error) {
+	u, err := neturl.Parse(job.URL)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.FromSlash(u.Path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []extract.SourceLink
+	if strings.EqualFold(filepath.Ext(path), ".rst") {
+		links = extract.RSTLinks(data)
+	} else {
+		links = extract.MarkdownLinks(data)
+	}
+
+	rel, err := filepath.Rel(f.root, path)
+	if err != nil {
+		rel = path
+	}
+	byLine := make(map[int]*pageFrame)
+	for _, link := range links {
+		value := link.Value
+		// Site-absolute paths point into the repository, not the filesystem root
+		if strings.HasPrefix(value, "/") && !strings.HasPrefix(value, "//") {
+			value = f.rootURL.String() + strings.TrimPrefix(value, "/")
+		}
+
+		frame, ok := byLine[link.Line]
+		if !ok {
+			frame = &pageFrame{URL: fmt.Sprintf("%s:%d", filepath.ToSlash(rel), link.Line), Base: u}
+			byLine[link.Line] = frame
+		}
+		frame.Items = append(frame.Items, frameItem{
+			Rule:    extract.Rule{Type: link.Type},
+			Value:   value,
+			Context: types.LinkContext{Line: link.Line},
+		})
+	}
+
+	page := &fetchedPage{URL: job.URL, StatusCode: 200}
+	for _, frame := range byLine {
+		page.Frames = append(page.Frames, *frame)
+	}
+	sort.Slice(page.Frames, func(i, j int) bool {
+		return len(page.Frames[i].URL) < len(page.Frames[j].URL) ||
+			len(page.Frames[i].URL) == len(page.Frames[j].URL) && page.Frames[i].URL < page.Frames[j].URL
+	})
+	return page, nil
+}
+
+func (f *sourceFetcher) Close() {}
@@ -0,0 +1,164 @@
+package worker
+
+import (
+	"net/url"
+
+	"github.com/MdSadiqMd/Scrape404/package/extract"
+	"github.com/MdSadiqMd/Scrape404/package/types"
+	"github.com/MdSadiqMd/Scrape404/package/utils"
+	"github.com/fatih/color"
+)
+
+const (
+	maxManifestSize   = 1 << 20
+	maxStylesheetSize = 2 << 20
+)
+
+// checkResource checks a newly seen link and, for stylesheets, manifests and
+// PDFs, the URLs inside them. The caller holds the lock and has marked link visited.
+func checkResource(link, page string, ref extract.Ref, cfg types.ScanConfig, visitedLinks map[string]bool, deadLinks *[]types.DeadLink, infoColor, successColor, errorColor *color.Color) {
+	utils.CheckLink(link, page, ref.Type, deadLinks, infoColor, successColor, errorColor)
+	checkEmbedded(link, page, ref, cfg, visitedLinks, deadLinks, infoColor, successColor, errorColor)
+}
+
+// checkEmbedded checks the URLs inside stylesheets, manifests and PDFs
+func checkEmbedded(link, page string, ref extract.Ref, cfg types.ScanConfig, visitedLinks map[string]bool, deadLinks *[]types.DeadLink, infoColor, successColor, errorColor *color.Color) {
+	if ref.Type == "manifest" {
+		checkManifestIcons(link, page, visitedLinks, deadLinks, infoColor, successColor, errorColor)
+	}
+	if ref.Stylesheet {
+		checkStylesheet(link, page, cfg.URL, visitedLinks, deadLinks, infoColor, successColor, errorColor)
+	}
+	if cfg.CheckPDFs && extract.IsPDF(link) {
+		checkPDF(link, cfg, visitedLinks, deadLinks, infoColor, successColor, errorColor)
+	}
+}
+
+// checkManifestIcons checks the icons listed in a web app manifest
+func checkManifestIcons(manifestURL, page string, visitedLinks map[string]bool, deadLinks *[]types.DeadLink, infoColor, successColor, errorColor *color.Color) {
+	data, _, err := utils.FetchBody(manifestURL, maxManifestSize)
+	if err != nil {
+		// A missing manifest is already reported by CheckLink
+		return
+	}
+
+	icons, err := extract.ManifestIcons(data, manifestURL)
+	if err != nil {
+		errorColor.Printf("⚠️  Invalid manifest %s: %s\n", manifestURL, err)
+		return
+	}
+	for _, icon := range icons {
+		if visitedLinks[icon] {
+			continue
+		}
+		visitedLinks[icon] = true
+		utils.CheckLinkIn(icon, page, manifestURL, extract.ManifestIconType, deadLinks, infoColor, successColor, errorColor)
+	}
+}
+
+// checkStylesheet downloads a same-site stylesheet and checks its url() and
+// @import references, following imports into further stylesheets
+func checkStylesheet(cssURL, page, siteURL string, visitedLinks map[string]bool, deadLinks *[]types.DeadLink, infoColor, successColor, errorColor *color.Color) {
+	if !utils.SameHost(cssURL, siteURL) {
+		return
+	}
+	base, err := url.Parse(cssURL)
+	if err != nil {
+		return
+	}
+	data, _, err := utils.FetchBody(cssURL, maxStylesheetSize)
+	if err != nil {
+		return
+	}
+
+	for _, ref := range extract.CSSRefs(string(data)) {
+		link := extract.Resolve(base, ref.Value)
+		if extract.Skip(link) || visitedLinks[link] {
+			continue
+		}
+		visitedLinks[link] = true
+		utils.CheckLinkIn(link, page, cssURL, ref.Type, deadLinks, infoColor, successColor, errorColor)
+		if ref.Stylesheet {
+			checkStylesheet(link, page, siteURL, visitedLinks, deadLinks, infoColor, successColor, errorColor)
+		}
+	}
+}
+
+// checkPDF downloads a same-site PDF and checks the links in its URI
+// annotations, reporting the PDF itself as the page they were found on
+func checkPDF(pdfURL string, cfg types.ScanConfig, visitedLinks map[string]bool, deadLinks *[]types.DeadLink, infoColor, successColor, errorColor *color.Color) {
+	if !utils.SameHost(pdfURL, cfg.URL) {
+		return
+	}
+
+	maxSize := int64(cfg.MaxPDFSizeMB) << 20
+	data, contentType, err := utils.FetchBody(pdfURL, maxSize+1)
+	if err != nil || !extract.IsPDFContent(contentType, data) {
+		return
+	}
+	if int64(len(data)) > maxSize {
+		infoColor.Printf("Skipping PDF larger than %dMB: %s\n", cfg.MaxPDFSizeMB, pdfURL)
+		return
+	}
+
+	for _, link := range extract.PDFLinks(data, pdfURL) {
+		if extract.Skip(link) || visitedLinks[link] {
+			continue
+		}
+		visitedLinks[link] = true
+		utils.CheckLink(link, pdfURL, extract.PDFLinkType, deadLinks, infoColor, successColor, errorColor)
+	}
+}
+
+// addLinkContext locates dead link among the dead links a check just found.
+// Links found inside the target, such as stylesheet URLs, keep their own place.
+func addLinkContext(found []types.DeadLink, link string, context types.LinkContext) {
+	for i := range found {
+		if found[i].URL == link && found[i].Source == "" {
+			found[i].LinkContext = context
+		}
+	}
+}
+
+// maxReferringPages caps the pages kept per dead link, PageCount still counts all
+const maxReferringPages = 100
+
+// trackDeadLinks starts the page list of new dead links from index from on
+// and indexes them in deadBy
+func trackDeadLinks(deadLinks []types.DeadLink, from int, deadBy map[string][]int) {
+	for i := from; i < len(deadLinks); i++ {
+		if deadLinks[i].PageCount > 0 {
+			continue
+		}
+		deadLinks[i].Pages = []string{deadLinks[i].FoundOn}
+		deadLinks[i].PageCount = 1
+		key := checkedURL(deadLinks[i])
+		deadBy[key] = append(deadBy[key], i)
+	}
+}
+
+// checkedURL is the URL whose check found link: the link itself, or the
+// stylesheet or manifest it is in
+func checkedURL(link types.DeadLink) string {
+	if link.Source != "" {
+		return link.Source
+	}
+	return link.URL
+}
+
+func addReferrer(link *types.DeadLink, page string) {
+	link.PageCount++
+	if len(link.Pages) < maxReferringPages {
+		link.Pages = append(link.Pages, page)
+	}
+}
+
+// foundDeadLink reports whether link itself is among the dead links a check just found
+func foundDeadLink(found []types.DeadLink, link string) bool {
+	for _, dead := range found {
+		if dead.URL == link && dead.Source == "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package worker
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/MdSadiqMd/Scrape404/package/types"
+	"github.com/playwright-community/playwright-go"
+)
+
+// requestBlocker aborts subresource requests by Playwright resource type or URL
+// glob while rendering, their URLs are checked over plain HTTP afterwards
+type requestBlocker struct {
+	resourceTypes map[string]bool
+	patterns      []*regexp.Regexp
+}
+
+func newRequestBlocker(cfg types.ScanConfig) (*requestBlocker, error) {
+	b := &requestBlocker{resourceTypes: make(map[string]bool)}
+	for _, resourceType := range cfg.BlockResourceTypes {
+		if resourceType = strings.ToLower(strings.TrimSpace(resourceType)); resourceType != "" {
+			b.resourceTypes[resourceType] = true
+		}
+	}
+	for _, pattern := range cfg.BlockURLPatterns {
+		re, err := globRegexp(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid block pattern %q: %w", pattern, err)
+		}
+		b.patterns = append(b.patterns, re)
+	}
+	if len(b.resourceTypes) == 0 && len(b.patterns) == 0 {
+		return nil, nil
+	}
+	return b, nil
+}
+
+// globRegexp turns a URL glob where * matches anything into an anchored regexp
+func globRegexp(pattern string) (*regexp.Regexp, error) {
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.Compile("^" + strings.Join(parts, ".*") + "$")
+}
+
+func (b *requestBlocker) blocks(req playwright.Request) bool {
+	if b.resourceTypes[req.ResourceType()] {
+		return true
+	}
+	for _, re := range b.patterns {
+		if re.MatchString(req.URL()) {
+			return true
+		}
+	}
+	return false
+}
+
+// install routes every request of page through the blocker, requests it lets
+// through fall back to the context routes that inject credentials
+func (b *requestBlocker) install(page playwright.Page, requests *networkRecorder) error {
+	return page.Route("**/*", func(route playwright.Route) {
+		req := route.Request()
+		if isPageNavigation(page, req) || !b.blocks(req) {
+			route.Fallback()
+			return
+		}
+		requests.add(networkResult{URL: req.URL(), ResourceType: req.ResourceType(), Blocked: true})
+		route.Abort("blockedbyclient")
+	})
+}
@@ -0,0 +1,122 @@
+package worker
+
+import (
+	"fmt"
+
+	"github.com/MdSadiqMd/Scrape404/package/types"
+	"github.com/MdSadiqMd/Scrape404/package/utils"
+	"github.com/fatih/color"
+	"github.com/playwright-community/playwright-go"
+)
+
+// startPlaywright installs the driver and the selected browser unless told to
+// skip, then starts the driver
+func startPlaywright(bc types.BrowserConfig, infoColor *color.Color) (*playwright.Playwright, error) {
+	options := &playwright.RunOptions{
+		DriverDirectory: bc.DriverDir,
+		Browsers:        []string{engineName(bc)},
+		// A custom executable means the bundled browser is never used
+		SkipInstallBrowsers: bc.ExecutablePath != "",
+	}
+
+	if bc.SkipInstall {
+		infoColor.Println("Skipping Playwright install, using the existing driver and browsers")
+	} else if err := playwright.Install(options); err != nil {
+		return nil, fmt.Errorf("installing Playwright: %w", err)
+	}
+
+	pw, err := playwright.Run(options)
+	if err != nil {
+		return nil, fmt.Errorf("starting Playwright: %w", err)
+	}
+	return pw, nil
+}
+
+// launchBrowser starts Playwright and the configured browser, the caller stops
+// both with pw.Stop
+func launchBrowser(cfg types.ScanConfig, infoColor *color.Color) (*playwright.Playwright, playwright.Browser, error) {
+	pw, err := startPlaywright(cfg.Browser, infoColor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	engine, err := browserType(pw, cfg.Browser)
+	if err != nil {
+		pw.Stop()
+		return nil, nil, err
+	}
+	infoColor.Printf("Browser: %s\n", engine.Name())
+
+	var options playwright.BrowserTypeLaunchOptions
+	applyBrowserLaunchOptions(&options, cfg.Browser)
+	if err := applyNetworkLaunchOptions(&options, utils.Network(), engine.Name() == types.EngineChromium); err != nil {
+		pw.Stop()
+		return nil, nil, fmt.Errorf("configuring browser network: %w", err)
+	}
+	browser, err := engine.Launch(options)
+	if err != nil {
+		pw.Stop()
+		return nil, nil, fmt.Errorf("launching browser: %w", err)
+	}
+	return pw, browser, nil
+}
+
+// newContextOptions returns the options every crawl context is created with
+func newContextOptions(cfg types.ScanConfig, storageState *playwright.OptionalStorageState) playwright.BrowserNewContextOptions {
+	options := playwright.BrowserNewContextOptions{
+		UserAgent:    playwright.String(cfg.UserAgent),
+		StorageState: storageState,
+	}
+	applyBrowserContextOptions(&options, cfg.Browser)
+	applyNetworkContextOptions(&options, utils.Network())
+	return options
+}
+
+func engineName(bc types.BrowserConfig) string {
+	if bc.Engine == "" {
+		return types.EngineChromium
+	}
+	return bc.Engine
+}
+
+func browserType(pw *playwright.Playwright, bc types.BrowserConfig) (playwright.BrowserType, error) {
+	switch engineName(bc) {
+	case types.EngineChromium:
+		return pw.Chromium, nil
+	case types.EngineFirefox:
+		return pw.Firefox, nil
+	case types.EngineWebKit:
+		return pw.WebKit, nil
+	}
+	return nil, fmt.Errorf("unknown browser engine %q, use chromium, firefox or webkit", bc.Engine)
+}
+
+func applyBrowserLaunchOptions(opts *playwright.BrowserTypeLaunchOptions, bc types.BrowserConfig) {
+	opts.Headless = playwright.Bool(!bc.Headed)
+	if bc.ExecutablePath != "" {
+		opts.ExecutablePath = playwright.String(bc.ExecutablePath)
+	}
+}
+
+// waitUntilState maps the configured load event, defaulting to network idle
+func waitUntilState(waitUntil string) *playwright.WaitUntilState {
+	switch waitUntil {
+	case "load":
+		return playwright.WaitUntilStateLoad
+	case "domcontentloaded":
+		return playwright.WaitUntilStateDomcontentloaded
+	}
+	return playwright.WaitUntilStateNetworkidle
+}
+
+func applyBrowserContextOptions(opts *playwright.BrowserNewContextOptions, bc types.BrowserConfig) {
+	if bc.ViewportWidth > 0 && bc.ViewportHeight > 0 {
+		opts.Viewport = &playwright.Size{Width: bc.ViewportWidth, Height: bc.ViewportHeight}
+	}
+	if bc.Locale != "" {
+		opts.Locale = playwright.String(bc.Locale)
+	}
+	if bc.Timezone != "" {
+		opts.TimezoneId = playwright.String(bc.Timezone)
+	}
+}
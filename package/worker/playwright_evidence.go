@@ -0,0 +1,91 @@
+package worker
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/MdSadiqMd/Scrape404/package/types"
+	"github.com/playwright-community/playwright-go"
+)
+
+// Evidence is kept outside the per-scan directories so repeat runs overwrite it
+const evidenceDir = "evidence"
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// evidenceFile returns a stable path, relative to the results directory, for a
+// capture of pageURL, readable from the URL and unique through its hash
+func evidenceFile(pageURL, ext string) string {
+	name := pageURL
+	if u, err := neturl.Parse(pageURL); err == nil {
+		name = u.Host + u.Path
+	}
+	name = strings.Trim(unsafeFileChars.ReplaceAllString(name, "_"), "_")
+	if len(name) > 80 {
+		name = name[:80]
+	}
+
+	sum := sha1.Sum([]byte(pageURL))
+	return filepath.Join(evidenceDir, fmt.Sprintf("%s-%s.%s", name, hex.EncodeToString(sum[:4]), ext))
+}
+
+// evidenceLog collects the screenshots and HAR files captured during a scan
+type evidenceLog struct {
+	mu     sync.Mutex
+	order  []string
+	byPage map[string]types.PageEvidence
+}
+
+func newEvidenceLog(restored []types.PageEvidence) *evidenceLog {
+	l := &evidenceLog{byPage: make(map[string]types.PageEvidence)}
+	for _, e := range restored {
+		l.add(e)
+	}
+	return l
+}
+
+func (l *evidenceLog) add(e types.PageEvidence) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	existing, ok := l.byPage[e.Page]
+	if !ok {
+		l.order = append(l.order, e.Page)
+	}
+	if e.Screenshot == "" {
+		e.Screenshot = existing.Screenshot
+	}
+	if e.HAR == "" {
+		e.HAR = existing.HAR
+	}
+	l.byPage[e.Page] = e
+}
+
+func (l *evidenceLog) Evidence() []types.PageEvidence {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	evidence := make([]types.PageEvidence, 0, len(l.order))
+	for _, page := range l.order {
+		evidence = append(evidence, l.byPage[page])
+	}
+	return evidence
+}
+
+// captureScreenshot saves a full-page screenshot of page and returns its relative path
+func captureScreenshot(page playwright.Page, resultsDir, pageURL string) (string, error) {
+	rel := evidenceFile(pageURL, "png")
+	if err := os.MkdirAll(filepath.Join(resultsDir, evidenceDir), 0o755); err != nil {
+		return "", err
+	}
+	_, err := page.Screenshot(playwright.PageScreenshotOptions{
+		Path:     playwright.String(filepath.Join(resultsDir, rel)),
+		FullPage: playwright.Bool(true),
+	})
+	return rel, err
+}
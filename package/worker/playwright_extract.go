@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"fmt"
+	neturl "net/url"
+
+	"github.com/MdSadiqMd/Scrape404/package/extract"
+	"github.com/MdSadiqMd/Scrape404/package/types"
+	"github.com/playwright-community/playwright-go"
+)
+
+// extractFrames runs the extraction script in page and decodes its result
+func extractFrames(page playwright.Page, pageURL string) ([]pageFrame, error) {
+	extracted, err := page.Evaluate(extract.PlaywrightScript())
+	if err != nil {
+		return nil, err
+	}
+	result, ok := extracted.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected extraction result %T", extracted)
+	}
+
+	rawFrames, _ := result["frames"].([]interface{})
+	frames := make([]pageFrame, 0, len(rawFrames))
+	for _, f := range rawFrames {
+		raw, _ := f.(map[string]interface{})
+		frame := pageFrame{URL: pageURL}
+		if frameURL, _ := raw["url"].(string); frameURL != "" {
+			frame.URL = frameURL
+		}
+		baseURI, _ := raw["baseURI"].(string)
+		if frame.Base, err = neturl.Parse(baseURI); err != nil || baseURI == "" {
+			frame.Base, _ = neturl.Parse(frame.URL)
+		}
+
+		items, _ := raw["items"].([]interface{})
+		for _, item := range items {
+			triple, _ := item.([]interface{})
+			if len(triple) != 3 {
+				continue
+			}
+			index, _ := triple[0].(float64)
+			value, _ := triple[1].(string)
+			if int(index) < 0 || int(index) >= len(extract.Rules) {
+				continue
+			}
+			rule := extract.Rules[int(index)]
+			context, _ := triple[2].(map[string]interface{})
+			text, _ := context["text"].(string)
+			selector, _ := context["selector"].(string)
+			tag, _ := context["tag"].(string)
+			frame.Items = append(frame.Items, frameItem{
+				Rule:    rule,
+				Value:   value,
+				Context: types.LinkContext{Text: text, Selector: selector, Tag: tag, Attr: rule.Attr},
+			})
+		}
+		frames = append(frames, frame)
+	}
+	return frames, nil
+}
@@ -0,0 +1,162 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/MdSadiqMd/Scrape404/package/types"
+	"github.com/playwright-community/playwright-go"
+)
+
+const rejectionMarker = "__scrape404_unhandled_rejection__ "
+
+// rejectionScript turns unhandled promise rejections into marked console errors
+// and stops the browser from also reporting them as page errors
+var rejectionScript = `window.addEventListener('unhandledrejection', event => {
+	const reason = event.reason;
+	const message = reason && reason.stack ? reason.stack : String(reason);
+	console.error('` + rejectionMarker + `' + message);
+	event.preventDefault();
+});`
+
+var stackLocationPattern = regexp.MustCompile(`(?:https?|file)://[^\s()]+:\d+:\d+`)
+
+// jsErrorLog deduplicates JavaScript errors across all pages of a scan
+type jsErrorLog struct {
+	mu     sync.Mutex
+	groups map[string]*types.JSError
+	pages  map[string]map[string]bool
+}
+
+func newJSErrorLog(restored []types.JSError) *jsErrorLog {
+	log := &jsErrorLog{groups: make(map[string]*types.JSError), pages: make(map[string]map[string]bool)}
+	for _, e := range restored {
+		e := e
+		key := e.Kind + "\x00" + e.Message + "\x00" + e.Location
+		log.groups[key] = &e
+		log.pages[key] = make(map[string]bool)
+		for _, p := range e.Pages {
+			log.pages[key][p] = true
+		}
+	}
+	return log
+}
+
+// watch collects console errors, uncaught exceptions and unhandled rejections of
+// page, attributing them to whatever URL pageURL returns at the time
+func (l *jsErrorLog) watch(context playwright.BrowserContext, page playwright.Page, pageURL func() string) error {
+	if err := context.AddInitScript(playwright.Script{Content: playwright.String(rejectionScript)}); err != nil {
+		return err
+	}
+
+	page.OnConsole(func(msg playwright.ConsoleMessage) {
+		if msg.Type() != "error" {
+			return
+		}
+		location := ""
+		if loc := msg.Location(); loc != nil && loc.URL != "" {
+			location = fmt.Sprintf("%s:%d:%d", loc.URL, loc.LineNumber+1, loc.ColumnNumber+1)
+		}
+
+		text := msg.Text()
+		if strings.HasPrefix(text, rejectionMarker) {
+			text = strings.TrimPrefix(text, rejectionMarker)
+			message, stack, _ := strings.Cut(text, "\n")
+			l.add(types.JSErrorUnhandledRejection, message, stackLocation(stack, location), pageURL())
+			return
+		}
+		l.add(types.JSErrorConsole, text, location, pageURL())
+	})
+
+	page.OnPageError(func(err error) {
+		message, stack := err.Error(), ""
+		var pwErr *playwright.Error
+		if errors.As(err, &pwErr) {
+			stack = pwErr.Stack
+			if pwErr.Name != "" {
+				message = pwErr.Name + ": " + pwErr.Message
+			}
+		}
+		l.add(types.JSErrorException, message, stackLocation(stack, ""), pageURL())
+	})
+	return nil
+}
+
+func (l *jsErrorLog) add(kind, message, location, pageURL string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := kind + "\x00" + message + "\x00" + location
+	group, ok := l.groups[key]
+	if !ok {
+		group = &types.JSError{Kind: kind, Message: message, Location: location}
+		l.groups[key] = group
+		l.pages[key] = make(map[string]bool)
+	}
+	group.Count++
+	if !l.pages[key][pageURL] {
+		l.pages[key][pageURL] = true
+		group.Pages = append(group.Pages, pageURL)
+	}
+}
+
+// OnPage reports whether any error was seen on pageURL
+func (l *jsErrorLog) OnPage(pageURL string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, pages := range l.pages {
+		if pages[pageURL] {
+			return true
+		}
+	}
+	return false
+}
+
+// Errors returns the grouped errors, most frequent first
+func (l *jsErrorLog) Errors() []types.JSError {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	errs := make([]types.JSError, 0, len(l.groups))
+	for _, group := range l.groups {
+		e := *group
+		e.Pages = append([]string(nil), group.Pages...)
+		errs = append(errs, e)
+	}
+	sort.Slice(errs, func(i, j int) bool {
+		if errs[i].Count != errs[j].Count {
+			return errs[i].Count > errs[j].Count
+		}
+		return errs[i].Message < errs[j].Message
+	})
+	return errs
+}
+
+// stackLocation returns the first script position in a stack trace, or fallback
+func stackLocation(stack, fallback string) string {
+	if loc := stackLocationPattern.FindString(stack); loc != "" {
+		return loc
+	}
+	return fallback
+}
+
+// checkJSErrorThresholds fails the run when a configured limit is exceeded, a negative limit disables it
+func checkJSErrorThresholds(errs []types.JSError, cfg types.ScanConfig) error {
+	exceptions := 0
+	for _, e := range errs {
+		if e.Kind != types.JSErrorConsole {
+			exceptions++
+		}
+	}
+	if cfg.MaxJSErrors >= 0 && len(errs) > cfg.MaxJSErrors {
+		return fmt.Errorf("%d distinct JavaScript errors, limit is %d", len(errs), cfg.MaxJSErrors)
+	}
+	if cfg.MaxJSExceptions >= 0 && exceptions > cfg.MaxJSExceptions {
+		return fmt.Errorf("%d distinct uncaught exceptions or rejections, limit is %d", exceptions, cfg.MaxJSExceptions)
+	}
+	return nil
+}
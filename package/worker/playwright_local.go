@@ -0,0 +1,36 @@
+package worker
+
+import (
+	"net/http"
+
+	"github.com/MdSadiqMd/Scrape404/package/utils"
+	"github.com/playwright-community/playwright-go"
+)
+
+// maxLocalFile caps the files served to the browser from a local site
+const maxLocalFile = 64 << 20
+
+// serveLocalSite answers the browser's requests for URLs of the local site
+// from its files, so pages of a built site render without a server
+func serveLocalSite(context playwright.BrowserContext, site *utils.LocalSite) error {
+	return context.Route("**/*", func(route playwright.Route) {
+		_, local, exists := site.Resolve(route.Request().URL())
+		if !local {
+			route.Fallback()
+			return
+		}
+		if !exists {
+			route.Fulfill(playwright.RouteFulfillOptions{Status: playwright.Int(http.StatusNotFound), Body: "Not Found"})
+			return
+		}
+		body, contentType, err := utils.FetchBody(route.Request().URL(), maxLocalFile)
+		if err != nil {
+			route.Fulfill(playwright.RouteFulfillOptions{Status: playwright.Int(http.StatusInternalServerError), Body: err.Error()})
+			return
+		}
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		route.Fulfill(playwright.RouteFulfillOptions{Status: playwright.Int(http.StatusOK), ContentType: playwright.String(contentType), Body: body})
+	})
+}
@@ -0,0 +1,192 @@
+package worker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/MdSadiqMd/Scrape404/package/types"
+	"github.com/MdSadiqMd/Scrape404/package/utils"
+	"github.com/fatih/color"
+	"github.com/playwright-community/playwright-go"
+)
+
+// loadStorageState returns the storage state every crawl context starts from.
+// A saved state file is reused while its session is still logged in, otherwise
+// the login flow runs and its result is written to the state file so later runs
+// can skip it. Without -storage-state the file is kept in the results directory,
+// keyed by the login URL, so it outlives the scan.
+func loadStorageState(browser playwright.Browser, cfg types.ScanConfig, infoColor *color.Color) (*playwright.OptionalStorageState, error) {
+	if cfg.LoginConfig == "" && cfg.StorageState == "" {
+		return nil, nil
+	}
+
+	var flow *types.LoginFlow
+	if cfg.LoginConfig != "" {
+		var err error
+		if flow, err = loadLoginFlow(cfg.LoginConfig); err != nil {
+			return nil, err
+		}
+	}
+	statePath := cfg.StorageState
+	if statePath == "" {
+		sum := sha256.Sum256([]byte(flow.URL))
+		statePath = filepath.Join(cfg.ResultsDir, "storage-state-"+hex.EncodeToString(sum[:6])+".json")
+	}
+
+	data, err := os.ReadFile(statePath)
+	if err == nil {
+		state := playwright.StorageState{}
+		if err := json.Unmarshal(data, &state); err != nil {
+			return nil, fmt.Errorf("parsing storage state %s: %w", statePath, err)
+		}
+		if flow == nil || loggedIn(browser, flow, cfg, &state) {
+			infoColor.Printf("Reusing saved login state from %s\n", statePath)
+			return state.ToOptionalStorageState(), nil
+		}
+		infoColor.Printf("Saved login state in %s has expired\n", statePath)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if flow == nil {
+		return nil, fmt.Errorf("storage state %s does not exist and no login config was given", statePath)
+	}
+
+	infoColor.Printf("Logging in at %s\n", flow.URL)
+	state, err := runLogin(browser, flow, cfg, statePath)
+	if err != nil {
+		return nil, fmt.Errorf("login failed: %w", err)
+	}
+	infoColor.Printf("Login state saved to %s\n", statePath)
+	return state.ToOptionalStorageState(), nil
+}
+
+// loggedIn reports whether a saved session is still valid: none of its cookies
+// has expired and the login page no longer shows the field the flow fills first
+func loggedIn(browser playwright.Browser, flow *types.LoginFlow, cfg types.ScanConfig, state *playwright.StorageState) bool {
+	now := float64(time.Now().Unix())
+	for _, cookie := range state.Cookies {
+		// Session cookies have no expiry, -1
+		if cookie.Expires > 0 && cookie.Expires < now {
+			return false
+		}
+	}
+
+	selector := ""
+	for _, step := range flow.Steps {
+		if step.Action == "fill" {
+			selector = step.Selector
+			break
+		}
+	}
+	if selector == "" {
+		return true
+	}
+
+	context, err := browser.NewContext(newContextOptions(cfg, state.ToOptionalStorageState()))
+	if err != nil {
+		return false
+	}
+	defer context.Close()
+	if err := applyCredentials(context, utils.Credentials()); err != nil {
+		return false
+	}
+	page, err := context.NewPage()
+	if err != nil {
+		return false
+	}
+	page.SetDefaultTimeout(float64(cfg.TimeoutSec * 1000))
+	if _, err := page.Goto(flow.URL); err != nil {
+		return false
+	}
+	page.WaitForLoadState()
+	count, err := page.Locator(selector).Count()
+	return err == nil && count == 0
+}
+
+func loadLoginFlow(path string) (*types.LoginFlow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	flow := &types.LoginFlow{}
+	if err := json.Unmarshal(data, flow); err != nil {
+		return nil, fmt.Errorf("parsing login config %s: %w", path, err)
+	}
+	if flow.URL == "" {
+		return nil, fmt.Errorf("login config %s has no url", path)
+	}
+
+	// Keep secrets out of the config file by allowing $ENV references in values
+	for i := range flow.Steps {
+		flow.Steps[i].Value = os.ExpandEnv(flow.Steps[i].Value)
+	}
+	return flow, nil
+}
+
+func runLogin(browser playwright.Browser, flow *types.LoginFlow, cfg types.ScanConfig, statePath string) (*playwright.StorageState, error) {
+	context, err := browser.NewContext(newContextOptions(cfg, nil))
+	if err != nil {
+		return nil, err
+	}
+	defer context.Close()
+
+	if err := applyCredentials(context, utils.Credentials()); err != nil {
+		return nil, err
+	}
+
+	page, err := context.NewPage()
+	if err != nil {
+		return nil, err
+	}
+	page.SetDefaultTimeout(float64(cfg.TimeoutSec * 1000))
+
+	if _, err := page.Goto(flow.URL); err != nil {
+		return nil, err
+	}
+
+	for i, step := range flow.Steps {
+		if err := runLoginStep(page, step); err != nil {
+			return nil, fmt.Errorf("step %d (%s): %w", i+1, step.Action, err)
+		}
+	}
+
+	if err := page.WaitForLoadState(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(statePath), 0o755); err != nil {
+		return nil, err
+	}
+	return context.StorageState(statePath)
+}
+
+func runLoginStep(page playwright.Page, step types.LoginStep) error {
+	switch step.Action {
+	case "fill":
+		return page.Fill(step.Selector, step.Value)
+	case "click":
+		return page.Click(step.Selector)
+	case "press":
+		return page.Press(step.Selector, step.Value)
+	case "wait":
+		switch {
+		case step.Selector != "":
+			_, err := page.WaitForSelector(step.Selector)
+			return err
+		case step.URL != "":
+			return page.WaitForURL(step.URL)
+		case step.TimeoutMs > 0:
+			page.WaitForTimeout(float64(step.TimeoutMs))
+			return nil
+		}
+		return errors.New("wait needs a selector, url or timeoutMs")
+	default:
+		return fmt.Errorf("unknown action %q", step.Action)
+	}
+}
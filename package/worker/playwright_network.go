@@ -0,0 +1,72 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/MdSadiqMd/Scrape404/package/network"
+	"github.com/playwright-community/playwright-go"
+)
+
+// applyNetworkLaunchOptions routes the browser through the configured proxy and
+// makes Chromium trust the extra CA certificates
+func applyNetworkLaunchOptions(opts *playwright.BrowserTypeLaunchOptions, netCfg *network.Config, chromium bool) error {
+	if netCfg == nil {
+		return nil
+	}
+
+	if netCfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(netCfg.ProxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		proxy := &playwright.Proxy{Server: proxyURL.Scheme + "://" + proxyURL.Host}
+		if proxyURL.User != nil {
+			proxy.Username = playwright.String(proxyURL.User.Username())
+			if password, ok := proxyURL.User.Password(); ok {
+				proxy.Password = playwright.String(password)
+			}
+		}
+		if len(netCfg.NoProxy) > 0 {
+			proxy.Bypass = playwright.String(strings.Join(netCfg.NoProxy, ","))
+		}
+		opts.Proxy = proxy
+	}
+
+	// Browsers keep their own trust store. Chromium can be told to skip
+	// verification of chains containing specific public keys, which does not
+	// make it trust the CAs: CA certificates are public, so a forged chain that
+	// includes one passes as well. Firefox and WebKit have no such option.
+	if !chromium {
+		if len(netCfg.CAFiles) > 0 && !netCfg.InsecureSkipVerify {
+			return errors.New("-ca-cert is only supported with -browser chromium, use -insecure-skip-verify with firefox or webkit")
+		}
+		return nil
+	}
+	hashes, err := netCfg.CASPKIHashes()
+	if err != nil {
+		return err
+	}
+	if len(hashes) > 0 {
+		opts.Args = append(opts.Args, "--ignore-certificate-errors-spki-list="+strings.Join(hashes, ","))
+	}
+	return nil
+}
+
+func applyNetworkContextOptions(opts *playwright.BrowserNewContextOptions, netCfg *network.Config) {
+	if netCfg == nil {
+		return
+	}
+	if netCfg.InsecureSkipVerify {
+		opts.IgnoreHttpsErrors = playwright.Bool(true)
+	}
+	for host, cc := range netCfg.ClientCerts {
+		opts.ClientCertificates = append(opts.ClientCertificates, playwright.ClientCertificate{
+			Origin:   "https://" + host,
+			CertPath: playwright.String(cc.CertFile),
+			KeyPath:  playwright.String(cc.KeyFile),
+		})
+	}
+}
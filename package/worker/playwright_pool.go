@@ -0,0 +1,159 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/MdSadiqMd/Scrape404/package/types"
+	"github.com/MdSadiqMd/Scrape404/package/utils"
+	"github.com/playwright-community/playwright-go"
+)
+
+// pageSlot is a browser context and page owned by one worker and reused
+// across URLs, optionally recreated every resetEvery pages
+type pageSlot struct {
+	browser    playwright.Browser
+	options    playwright.BrowserNewContextOptions
+	timeoutSec int
+	resetEvery int
+	exploreSPA bool
+	jsErrors   *jsErrorLog
+	blocker    *requestBlocker
+	// harPath is where the context records its HAR, empty when HAR capture is off
+	harPath string
+
+	context  playwright.BrowserContext
+	page     playwright.Page
+	requests *networkRecorder
+	uses     int
+
+	mu      sync.Mutex
+	current string
+}
+
+func newPageSlot(browser playwright.Browser, options playwright.BrowserNewContextOptions, cfg types.ScanConfig, jsErrors *jsErrorLog, blocker *requestBlocker, harPath string) (*pageSlot, error) {
+	s := &pageSlot{
+		browser:    browser,
+		options:    options,
+		timeoutSec: cfg.TimeoutSec,
+		resetEvery: cfg.ContextResetEvery,
+		exploreSPA: cfg.ExploreSPA,
+		jsErrors:   jsErrors,
+		blocker:    blocker,
+		harPath:    harPath,
+	}
+	return s, s.open()
+}
+
+func (s *pageSlot) open() error {
+	options := s.options
+	if s.harPath != "" {
+		options.RecordHarPath = playwright.String(s.harPath)
+		options.RecordHarContent = playwright.HarContentPolicyOmit
+	}
+	context, err := s.browser.NewContext(options)
+	if err != nil {
+		return err
+	}
+	if err := applyCredentials(context, utils.Credentials()); err != nil {
+		context.Close()
+		return err
+	}
+	// Registered last so it runs before the credentials route
+	if site := utils.Local(); site != nil {
+		if err := serveLocalSite(context, site); err != nil {
+			context.Close()
+			return err
+		}
+	}
+	if s.exploreSPA {
+		if err := context.AddInitScript(playwright.Script{Content: playwright.String(routeRecorderScript)}); err != nil {
+			context.Close()
+			return err
+		}
+	}
+
+	page, err := context.NewPage()
+	if err != nil {
+		context.Close()
+		return err
+	}
+	page.SetDefaultTimeout(float64(s.timeoutSec * 1000))
+
+	// Listeners stay attached for the page's lifetime and attribute events to the current URL
+	s.requests = recordNetwork(page)
+	if s.blocker != nil {
+		if err := s.blocker.install(page, s.requests); err != nil {
+			context.Close()
+			return err
+		}
+	}
+	if err := s.jsErrors.watch(context, page, s.currentURL); err != nil {
+		context.Close()
+		return err
+	}
+
+	s.context, s.page, s.uses = context, page, 0
+	return nil
+}
+
+func (s *pageSlot) Close() {
+	if s.context != nil {
+		s.context.Close()
+		s.context = nil
+	}
+	if s.harPath != "" {
+		os.Remove(s.harPath)
+	}
+}
+
+// FinishHAR closes the context so its HAR is written, then keeps it at dest or
+// discards it when dest is empty. The next Begin opens a fresh context, giving
+// every page a HAR of its own, at the cost of the cache, cookies and storage
+// the shared context would have kept.
+func (s *pageSlot) FinishHAR(dest string) error {
+	if s.context != nil {
+		s.context.Close()
+		s.context = nil
+	}
+	if dest == "" {
+		os.Remove(s.harPath)
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	return os.Rename(s.harPath, dest)
+}
+
+// Begin prepares the slot for visiting url, recreating the context when it is
+// due for a reset or its page was closed by a crash
+func (s *pageSlot) Begin(url string) error {
+	if s.context == nil || s.page.IsClosed() || (s.resetEvery > 0 && s.uses >= s.resetEvery) {
+		s.Close()
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+	// Leaving the previous page aborts its unfinished requests, their events
+	// must arrive before the recorder is reset rather than on the next page
+	if s.uses > 0 {
+		if _, err := s.page.Goto("about:blank", playwright.PageGotoOptions{WaitUntil: playwright.WaitUntilStateLoad}); err != nil {
+			return err
+		}
+	}
+	s.uses++
+
+	s.mu.Lock()
+	s.current = url
+	s.mu.Unlock()
+	s.requests.Reset()
+	return nil
+}
+
+func (s *pageSlot) currentURL() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current
+}
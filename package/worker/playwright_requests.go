@@ -0,0 +1,102 @@
+package worker
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+type networkResult struct {
+	URL          string
+	ResourceType string
+	Status       int
+	Failure      string
+	// Blocked requests were aborted on purpose and still need an HTTP check
+	Blocked bool
+}
+
+func (r networkResult) dead() bool {
+	return r.Failure != "" || r.Status >= 400
+}
+
+// networkRecorder collects the outcome of every subresource a page requested
+type networkRecorder struct {
+	mu      sync.Mutex
+	order   []string
+	results map[string]networkResult
+}
+
+func recordNetwork(page playwright.Page) *networkRecorder {
+	r := &networkRecorder{results: make(map[string]networkResult)}
+
+	page.OnResponse(func(resp playwright.Response) {
+		req := resp.Request()
+		if isPageNavigation(page, req) {
+			return
+		}
+		r.add(networkResult{URL: resp.URL(), ResourceType: req.ResourceType(), Status: resp.Status()})
+	})
+	page.OnRequestFailed(func(req playwright.Request) {
+		if isPageNavigation(page, req) {
+			return
+		}
+		failure := "request failed"
+		if err := req.Failure(); err != nil {
+			failure = err.Error()
+		}
+		// Requests cancelled by leaving the page say nothing about the link
+		if isAborted(failure) {
+			return
+		}
+		r.add(networkResult{URL: req.URL(), ResourceType: req.ResourceType(), Failure: failure})
+	})
+	return r
+}
+
+func (r *networkRecorder) add(result networkResult) {
+	lower := strings.ToLower(result.URL)
+	if strings.HasPrefix(lower, "data:") || strings.HasPrefix(lower, "blob:") {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.results[result.URL]
+	if !ok {
+		r.order = append(r.order, result.URL)
+	}
+	// A failure is never overwritten by a later success for the same URL, and
+	// the abort of a blocked request is not a failure
+	if !ok || (!existing.dead() && !existing.Blocked) {
+		r.results[result.URL] = result
+	}
+}
+
+// Reset forgets the requests of the previous page when the page is reused
+func (r *networkRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.order = nil
+	r.results = make(map[string]networkResult)
+}
+
+func (r *networkRecorder) Results() []networkResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	results := make([]networkResult, 0, len(r.order))
+	for _, u := range r.order {
+		results = append(results, r.results[u])
+	}
+	return results
+}
+
+// isAborted reports the cancellation errors of Chromium, Firefox and WebKit
+func isAborted(failure string) bool {
+	return strings.Contains(failure, "ERR_ABORTED") || strings.Contains(failure, "NS_BINDING_ABORTED") || failure == "cancelled"
+}
+
+// isPageNavigation reports the main document request, which is checked as the page itself
+func isPageNavigation(page playwright.Page, req playwright.Request) bool {
+	return req.IsNavigationRequest() && req.Frame() == page.MainFrame()
+}
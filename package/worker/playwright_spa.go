@@ -0,0 +1,120 @@
+package worker
+
+import (
+	neturl "net/url"
+	"strings"
+
+	"github.com/MdSadiqMd/Scrape404/package/extract"
+	"github.com/playwright-community/playwright-go"
+)
+
+const (
+	spaRouteType = "spa-route"
+	// Upper bound on clicks per page so large menus don't stall the crawl
+	maxExploreClicks = 50
+	exploreSettleMs  = 300
+)
+
+// routeRecorderScript runs before any page script and logs every URL the app
+// moves to through the History API
+const routeRecorderScript = `(() => {
+	window.__scrape404Routes = [];
+	for (const name of ['pushState', 'replaceState']) {
+		const original = history[name];
+		history[name] = function (state, title, url) {
+			if (url !== undefined && url !== null) {
+				try { window.__scrape404Routes.push(new URL(url, location.href).href); } catch (e) {}
+			}
+			return original.apply(this, arguments);
+		};
+	}
+})();`
+
+// Elements acting as client-side links inside navigation regions
+const navCandidates = `nav [role=link], nav [role=button], nav button, nav a:not([href]),
+	[role=navigation] [role=link], [role=navigation] [role=button], [role=navigation] button, [role=navigation] a:not([href])`
+
+// defaultExploreSkip keeps exploration away from controls that end the session
+// or change data, matched against their text, aria-label and title
+var defaultExploreSkip = []string{"logout", "log out", "sign out", "sign off", "delete", "remove", "unsubscribe", "deactivate"}
+
+// clickCandidateScript skips denied controls and form submit buttons, clicking a
+// submit button would post the form
+const clickCandidateScript = `({i, texts, selectors}) => {
+	const denied = el => {
+		if (el.closest('form') && el.type === 'submit') return true;
+		if (selectors.some(s => { try { return el.matches(s); } catch (e) { return false; } })) return true;
+		const label = [el.textContent, el.getAttribute('aria-label'), el.getAttribute('title')].join(' ').toLowerCase();
+		return texts.some(t => label.includes(t));
+	};
+	const els = [...document.querySelectorAll(` + "`" + navCandidates + "`" + `)]
+		.filter(el => el.offsetParent !== null && !el.disabled && !denied(el));
+	if (i >= els.length) return false;
+	els[i].click();
+	return true;
+}`
+
+const takeRoutesScript = `() => {
+	const routes = window.__scrape404Routes || [];
+	window.__scrape404Routes = [];
+	return [...routes, location.href];
+}`
+
+// exploreRoutes clicks the navigation elements of page one by one and returns
+// the routes the app navigated to, returning to pageURL after each click.
+// Elements whose text contains one of skipTexts or matching one of
+// skipSelectors are never clicked.
+func exploreRoutes(page playwright.Page, pageURL string, skipTexts, skipSelectors []string) []string {
+	base, err := neturl.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var routes []string
+	collect := func() {
+		taken, err := page.Evaluate(takeRoutesScript)
+		if err != nil {
+			return
+		}
+		list, _ := taken.([]interface{})
+		for _, item := range list {
+			route, _ := item.(string)
+			route = extract.Resolve(base, route)
+			if route == "" || route == pageURL || seen[route] {
+				continue
+			}
+			seen[route] = true
+			routes = append(routes, route)
+		}
+	}
+	collect()
+
+	texts := make([]string, 0, len(defaultExploreSkip)+len(skipTexts))
+	for _, text := range append(defaultExploreSkip, skipTexts...) {
+		texts = append(texts, strings.ToLower(text))
+	}
+	if skipSelectors == nil {
+		skipSelectors = []string{}
+	}
+	args := map[string]interface{}{"texts": texts, "selectors": skipSelectors}
+
+	for i := 0; i < maxExploreClicks; i++ {
+		args["i"] = i
+		clicked, err := page.Evaluate(clickCandidateScript, args)
+		if err != nil || clicked != true {
+			break
+		}
+		page.WaitForTimeout(exploreSettleMs)
+		collect()
+
+		if page.URL() != pageURL {
+			if _, err := page.GoBack(); err != nil || page.URL() != pageURL {
+				if _, err := page.Goto(pageURL); err != nil {
+					break
+				}
+			}
+		}
+	}
+	return routes
+}
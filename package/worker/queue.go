@@ -0,0 +1,70 @@
+package worker
+
+import "sync"
+
+type pageJob struct {
+	URL   string
+	Depth int
+	// Referrer is the page the URL was discovered on, empty for start and resumed pages
+	Referrer string
+}
+
+// workQueue hands pages to a fixed set of workers. Jobs in flight may push
+// more jobs, so the queue is only drained once it is empty and nothing is active.
+type workQueue struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	jobs    []pageJob
+	active  int
+	stopped bool
+}
+
+func newWorkQueue() *workQueue {
+	q := &workQueue{}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+func (q *workQueue) Push(job pageJob) {
+	q.mu.Lock()
+	q.jobs = append(q.jobs, job)
+	q.mu.Unlock()
+	q.cond.Signal()
+}
+
+// Pop blocks until a job is available, it returns false when the crawl is finished
+func (q *workQueue) Pop() (pageJob, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.jobs) == 0 && q.active > 0 && !q.stopped {
+		q.cond.Wait()
+	}
+	if len(q.jobs) == 0 || q.stopped {
+		return pageJob{}, false
+	}
+
+	job := q.jobs[0]
+	q.jobs = q.jobs[1:]
+	q.active++
+	return job, true
+}
+
+// Stop makes Pop return false, leaving the queued jobs in place
+func (q *workQueue) Stop() {
+	q.mu.Lock()
+	q.stopped = true
+	q.mu.Unlock()
+	q.cond.Broadcast()
+}
+
+// Done marks a popped job as finished
+func (q *workQueue) Done() {
+	q.mu.Lock()
+	q.active--
+	drained := q.active == 0 && len(q.jobs) == 0
+	q.mu.Unlock()
+	if drained {
+		q.cond.Broadcast()
+	}
+}
@@ -0,0 +1,59 @@
+package worker
+
+import (
+	"math/rand"
+	neturl "net/url"
+	"sync"
+	"time"
+)
+
+// hostLimiter keeps the crawl polite per host, like colly's LimitRule: at most
+// parallelism pages of a host are fetched at once, and each slot is held for
+// delay plus up to randomDelay after its page finishes.
+type hostLimiter struct {
+	parallelism int
+	delay       time.Duration
+	randomDelay time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]chan struct{}
+}
+
+func newHostLimiter(parallelism int, delay, randomDelay time.Duration) *hostLimiter {
+	return &hostLimiter{
+		parallelism: max(parallelism, 1),
+		delay:       delay,
+		randomDelay: randomDelay,
+		hosts:       make(map[string]chan struct{}),
+	}
+}
+
+// Acquire blocks until link's host has a free slot, the returned release
+// frees it once the delay has passed without holding up the worker
+func (l *hostLimiter) Acquire(link string) (release func()) {
+	host := link
+	if u, err := neturl.Parse(link); err == nil {
+		host = u.Host
+	}
+
+	l.mu.Lock()
+	slots, ok := l.hosts[host]
+	if !ok {
+		slots = make(chan struct{}, l.parallelism)
+		l.hosts[host] = slots
+	}
+	l.mu.Unlock()
+
+	slots <- struct{}{}
+	return func() {
+		wait := l.delay
+		if l.randomDelay > 0 {
+			wait += time.Duration(rand.Int63n(int64(l.randomDelay)))
+		}
+		if wait <= 0 {
+			<-slots
+			return
+		}
+		time.AfterFunc(wait, func() { <-slots })
+	}
+}
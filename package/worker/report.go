@@ -0,0 +1,16 @@
+package worker
+
+import (
+	"github.com/MdSadiqMd/Scrape404/package/report"
+	"github.com/MdSadiqMd/Scrape404/package/types"
+	"github.com/fatih/color"
+)
+
+func writeReport(r *report.Report, cfg types.ScanConfig, infoColor, errorColor *color.Color) {
+	path, err := report.Write(cfg.ResultsDir, r)
+	if err != nil {
+		errorColor.Printf("⚠️  Failed to write report: %s\n", err)
+		return
+	}
+	infoColor.Printf("\nReport saved to %s\n", path)
+}